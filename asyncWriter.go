@@ -0,0 +1,261 @@
+package rogu
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+var (
+	// ErrWriterClosed is returned when writing to
+	// a writer which has already been closed.
+	ErrWriterClosed = errors.New("writer has been closed")
+
+	// ErrFlushTimeout is returned when the queue of
+	// an AsyncWriter could not be drained within the
+	// given timeout.
+	ErrFlushTimeout = errors.New("flush timed out")
+)
+
+// OverflowPolicy specifies how an AsyncWriter
+// behaves when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the writing goroutine
+	// until there is space in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the entry which
+	// is about to be written.
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest entry
+	// in the queue to make space for the new one.
+	OverflowDropOldest
+	// OverflowDropBelowLevel drops the entry if its
+	// level is less severe than the DropLevel of the
+	// AsyncWriter and blocks otherwise.
+	OverflowDropBelowLevel
+)
+
+const defaultCloseTimeout = 5 * time.Second
+
+// AsyncWriter implements Writer and passes entries
+// to the wrapped Writer from a background goroutine.
+//
+// Written entries are copied into a bounded queue.
+// When the queue is full, the Policy specifies if
+// the write blocks or which entry is dropped.
+//
+// Errors returned by the wrapped Writer are passed
+// to OnError, if set.
+//
+// Close must be called to drain the queue and
+// to stop the background goroutine.
+type AsyncWriter struct {
+	// Policy specifies the behavior when the
+	// queue is full.
+	Policy OverflowPolicy
+	// DropLevel is the level used with the
+	// OverflowDropBelowLevel policy. Entries with
+	// a level less severe than DropLevel are
+	// dropped when the queue is full.
+	DropLevel level.Level
+	// CloseTimeout is the maximum duration Close
+	// waits for the queue to be drained.
+	CloseTimeout time.Duration
+	// OnError is called with errors returned by
+	// the wrapped Writer.
+	OnError func(err error)
+
+	w Writer
+
+	mtx      sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	// idle is closed when the queue has been
+	// drained. It is only created when Flush waits
+	// for the queue, so that waiters which timed
+	// out can simply abandon it.
+	idle chan struct{}
+
+	queue    []Entry
+	head     int
+	size     int
+	inFlight bool
+	closed   bool
+	done     chan struct{}
+
+	dropped atomic.Uint64
+}
+
+var (
+	_ Writer = (*AsyncWriter)(nil)
+	_ Closer = (*AsyncWriter)(nil)
+)
+
+// NewAsyncWriter returns a new AsyncWriter which
+// passes entries to w using a queue which can
+// hold up to size entries.
+func NewAsyncWriter(w Writer, size int) *AsyncWriter {
+	if size < 1 {
+		size = 1
+	}
+
+	t := &AsyncWriter{
+		CloseTimeout: defaultCloseTimeout,
+		DropLevel:    level.Warn,
+		w:            w,
+		queue:        make([]Entry, size),
+		done:         make(chan struct{}),
+	}
+
+	t.notEmpty = sync.NewCond(&t.mtx)
+	t.notFull = sync.NewCond(&t.mtx)
+
+	go t.run()
+
+	return t
+}
+
+// Write copies the entry into the queue.
+//
+// If the writer has been closed, ErrWriterClosed
+// is returned.
+func (t *AsyncWriter) Write(e Entry) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.closed {
+		return ErrWriterClosed
+	}
+
+	for t.size == len(t.queue) {
+		switch t.Policy {
+		case OverflowDropNewest:
+			t.dropped.Add(1)
+			return nil
+		case OverflowDropOldest:
+			t.queue[t.head] = Entry{}
+			t.head = (t.head + 1) % len(t.queue)
+			t.size--
+			t.dropped.Add(1)
+			continue
+		case OverflowDropBelowLevel:
+			if e.Level > t.DropLevel {
+				t.dropped.Add(1)
+				return nil
+			}
+		}
+
+		t.notFull.Wait()
+		if t.closed {
+			return ErrWriterClosed
+		}
+	}
+
+	t.queue[(t.head+t.size)%len(t.queue)] = e.Clone()
+	t.size++
+	t.notEmpty.Signal()
+
+	return nil
+}
+
+// Dropped returns the number of entries which
+// have been dropped because the queue was full.
+func (t *AsyncWriter) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+// Flush blocks until all queued entries have
+// been passed to the wrapped Writer or until the
+// timeout exceeds, in which case ErrFlushTimeout
+// is returned.
+func (t *AsyncWriter) Flush(timeout time.Duration) error {
+	t.mtx.Lock()
+	if t.size == 0 && !t.inFlight {
+		t.mtx.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	drained := t.idle
+	t.mtx.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+		return nil
+	case <-timer.C:
+		return ErrFlushTimeout
+	}
+}
+
+// Close stops accepting new entries, waits up to
+// CloseTimeout for the queue to be drained and
+// closes the wrapped Writer, if it is closable.
+func (t *AsyncWriter) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.notEmpty.Broadcast()
+	t.notFull.Broadcast()
+	t.mtx.Unlock()
+
+	timer := time.NewTimer(t.CloseTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-t.done:
+	case <-timer.C:
+		return ErrFlushTimeout
+	}
+
+	if c, ok := t.w.(Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (t *AsyncWriter) run() {
+	defer close(t.done)
+
+	for {
+		t.mtx.Lock()
+		for t.size == 0 && !t.closed {
+			t.notEmpty.Wait()
+		}
+		if t.size == 0 {
+			t.mtx.Unlock()
+			return
+		}
+
+		e := t.queue[t.head]
+		t.queue[t.head] = Entry{}
+		t.head = (t.head + 1) % len(t.queue)
+		t.size--
+		t.inFlight = true
+		t.notFull.Signal()
+		t.mtx.Unlock()
+
+		if err := t.w.Write(e); err != nil && t.OnError != nil {
+			t.OnError(err)
+		}
+
+		t.mtx.Lock()
+		t.inFlight = false
+		if t.size == 0 && t.idle != nil {
+			close(t.idle)
+			t.idle = nil
+		}
+		t.mtx.Unlock()
+	}
+}
@@ -0,0 +1,204 @@
+package rogu
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type blockingWriter struct {
+	mtx     sync.Mutex
+	release chan struct{}
+	msgs    []string
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{release: make(chan struct{})}
+}
+
+func (t *blockingWriter) Write(e Entry) error {
+	<-t.release
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.msgs = append(t.msgs, e.Message)
+	return nil
+}
+
+func (t *blockingWriter) messages() []string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return append([]string(nil), t.msgs...)
+}
+
+// fill writes n entries to w and waits until the
+// first entry has been picked up by the background
+// goroutine, so that n-1 entries remain queued.
+func fill(t *testing.T, w *AsyncWriter, lvl level.Level, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := w.Write(Entry{Level: lvl, Message: string(rune('a' + i))}); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			for {
+				w.mtx.Lock()
+				inFlight := w.inFlight
+				w.mtx.Unlock()
+				if inFlight {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+}
+
+func TestAsyncWriterDropNewest(t *testing.T) {
+	bw := newBlockingWriter()
+	w := NewAsyncWriter(bw, 2)
+	w.Policy = OverflowDropNewest
+
+	fill(t, w, level.Info, 5)
+	close(bw.release)
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Dropped() != 2 {
+		t.Errorf("expected 2 dropped entries, got %d", w.Dropped())
+	}
+	if msgs := bw.messages(); len(msgs) != 3 || msgs[2] != "c" {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestAsyncWriterDropOldest(t *testing.T) {
+	bw := newBlockingWriter()
+	w := NewAsyncWriter(bw, 2)
+	w.Policy = OverflowDropOldest
+
+	fill(t, w, level.Info, 5)
+	close(bw.release)
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Dropped() != 2 {
+		t.Errorf("expected 2 dropped entries, got %d", w.Dropped())
+	}
+	if msgs := bw.messages(); len(msgs) != 3 || msgs[1] != "d" || msgs[2] != "e" {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestAsyncWriterDropBelowLevel(t *testing.T) {
+	bw := newBlockingWriter()
+	w := NewAsyncWriter(bw, 1)
+	w.Policy = OverflowDropBelowLevel
+	w.DropLevel = level.Warn
+
+	fill(t, w, level.Info, 2)
+
+	if err := w.Write(Entry{Level: level.Debug}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", w.Dropped())
+	}
+
+	written := make(chan struct{})
+	go func() {
+		w.Write(Entry{Level: level.Error, Message: "err"})
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("error entry should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(bw.release)
+	<-written
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := bw.messages(); len(msgs) != 3 || msgs[2] != "err" {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestAsyncWriterFlush(t *testing.T) {
+	bw := newBlockingWriter()
+	w := NewAsyncWriter(bw, 10)
+
+	fill(t, w, level.Info, 3)
+
+	if err := w.Flush(10 * time.Millisecond); err != ErrFlushTimeout {
+		t.Errorf("expected flush timeout, got %v", err)
+	}
+
+	close(bw.release)
+
+	if err := w.Flush(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := bw.messages(); len(msgs) != 3 {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+
+	w.Close()
+	if err := w.Write(Entry{}); err != ErrWriterClosed {
+		t.Errorf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+func TestAsyncWriterFlushTimeouts(t *testing.T) {
+	bw := newBlockingWriter()
+	w := NewAsyncWriter(bw, 10)
+
+	fill(t, w, level.Info, 3)
+
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if err := w.Flush(time.Microsecond); err != ErrFlushTimeout {
+			t.Fatalf("expected flush timeout, got %v", err)
+		}
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("timed out flushes leaked %d goroutines", n-goroutines)
+	}
+
+	close(bw.release)
+
+	if err := w.Flush(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+}
+
+func TestAsyncWriterCopiesEntries(t *testing.T) {
+	var fw recordWriter
+	w := NewAsyncWriter(&fw, 100)
+	l := NewLogger(w)
+
+	for i := 0; i < 50; i++ {
+		l.Info().Field("i", i).Msg("msg")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i, rec := range fw.records {
+		if rec["i"] != i {
+			t.Errorf("entry %d has field i=%v", i, rec["i"])
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package rogu
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// BatchEncoder encodes a batch of entries into
+// the body of a request sent by the HTTPWriter.
+type BatchEncoder interface {
+	// ContentType returns the value of the
+	// Content-Type header of the request.
+	ContentType() string
+	// Encode appends the encoded entries to b.
+	Encode(b []byte, entries []Entry) ([]byte, error)
+}
+
+// NDJSONEncoder implements BatchEncoder and encodes
+// entries as newline delimited JSON objects like
+// the JsonWriter does.
+type NDJSONEncoder struct {
+	TimeFormat string
+	Schema     *JsonSchema
+}
+
+var _ BatchEncoder = (*NDJSONEncoder)(nil)
+
+// NewNDJSONEncoder returns a new NDJSONEncoder
+// using a copy of JsonSchemaFlat.
+func NewNDJSONEncoder() *NDJSONEncoder {
+	var t NDJSONEncoder
+
+	t.TimeFormat = time.RFC3339Nano
+	schema := *JsonSchemaFlat
+	t.Schema = &schema
+
+	return &t
+}
+
+func (t *NDJSONEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (t *NDJSONEncoder) Encode(b []byte, entries []Entry) (_ []byte, err error) {
+	jw := JsonWriter{TimeFormat: t.TimeFormat, Schema: t.Schema}
+	for _, e := range entries {
+		if b, err = jw.appendEntry(b, e); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// ElasticsearchEncoder implements BatchEncoder and
+// encodes entries as request body for the
+// Elasticsearch bulk API. Each entry is preceded
+// by a `create` action for Index, so Index can also
+// be the name of a data stream.
+//
+// Mind that the bulk API responds with status
+// 200 even if single entries were rejected.
+type ElasticsearchEncoder struct {
+	Index      string
+	TimeFormat string
+	Schema     *JsonSchema
+}
+
+var _ BatchEncoder = (*ElasticsearchEncoder)(nil)
+
+// NewElasticsearchEncoder returns a new
+// ElasticsearchEncoder writing to the given
+// index using a copy of JsonSchemaECS.
+func NewElasticsearchEncoder(index string) *ElasticsearchEncoder {
+	var t ElasticsearchEncoder
+
+	t.Index = index
+	t.TimeFormat = time.RFC3339Nano
+	schema := *JsonSchemaECS
+	t.Schema = &schema
+
+	return &t
+}
+
+func (t *ElasticsearchEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (t *ElasticsearchEncoder) Encode(b []byte, entries []Entry) (_ []byte, err error) {
+	jw := JsonWriter{TimeFormat: t.TimeFormat, Schema: t.Schema}
+	for _, e := range entries {
+		b = append(b, `{"create":{`...)
+		if t.Index != "" {
+			b = appendJsonKey(b, "_index")
+			b = appendJsonString(b, t.Index)
+		}
+		b = append(b, "}}\n"...)
+		if b, err = jw.appendEntry(b, e); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// LokiEncoder implements BatchEncoder and encodes
+// entries as request body for the Grafana Loki
+// push API (`/loki/api/v1/push`).
+//
+// Entries are grouped into streams labeled with
+// their `level` and `tag` in addition to the
+// static Labels. The log lines are formatted in
+// logfmt without timestamp.
+type LokiEncoder struct {
+	Labels map[string]string
+}
+
+var _ BatchEncoder = (*LokiEncoder)(nil)
+
+// NewLokiEncoder returns a new LokiEncoder
+// with the given static labels.
+func NewLokiEncoder(labels map[string]string) *LokiEncoder {
+	return &LokiEncoder{Labels: labels}
+}
+
+func (t *LokiEncoder) ContentType() string {
+	return "application/json"
+}
+
+type lokiStream struct {
+	tag     string
+	level   string
+	entries []int
+}
+
+func (t *LokiEncoder) Encode(b []byte, entries []Entry) ([]byte, error) {
+	var streams []*lokiStream
+	for i, e := range entries {
+		lvl := e.Level.String()
+		var s *lokiStream
+		for _, cs := range streams {
+			if cs.tag == e.Tag && cs.level == lvl {
+				s = cs
+				break
+			}
+		}
+		if s == nil {
+			s = &lokiStream{tag: e.Tag, level: lvl}
+			streams = append(streams, s)
+		}
+		s.entries = append(s.entries, i)
+	}
+
+	labelKeys := make([]string, 0, len(t.Labels))
+	for k := range t.Labels {
+		if k != "level" && k != "tag" {
+			labelKeys = append(labelKeys, k)
+		}
+	}
+	sort.Strings(labelKeys)
+
+	var lw LogfmtWriter
+
+	b = append(b, `{"streams":[`...)
+	for i, s := range streams {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = append(b, `{"stream":{`...)
+		for _, k := range labelKeys {
+			b = appendJsonNextKey(b, k)
+			b = appendJsonString(b, t.Labels[k])
+		}
+		b = appendJsonNextKey(b, "level")
+		b = appendJsonString(b, s.level)
+		if s.tag != "" {
+			b = appendJsonNextKey(b, "tag")
+			b = appendJsonString(b, s.tag)
+		}
+
+		b = append(b, `},"values":[`...)
+		for j, idx := range s.entries {
+			e := entries[idx]
+			if j > 0 {
+				b = append(b, ',')
+			}
+
+			ts := e.Time
+			if ts.IsZero() {
+				ts = time.Now()
+			}
+
+			var lb [bufferSize]byte
+			line := lw.appendEntry(lb[:0], e)
+
+			b = append(b, '[', '"')
+			b = strconv.AppendInt(b, ts.UnixNano(), 10)
+			b = append(b, '"', ',')
+			b = appendJsonString(b, line[:len(line)-1])
+			b = append(b, ']')
+		}
+		b = append(b, "]}"...)
+	}
+	b = append(b, "]}"...)
+
+	return b, nil
+}
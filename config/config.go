@@ -0,0 +1,178 @@
+// Package config allows to build a rogu.Logger from
+// a declarative configuration which can be loaded
+// from environment variables, JSON or YAML.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/level"
+	"gopkg.in/yaml.v3"
+)
+
+// Config specifies a rogu.Logger.
+//
+// Example YAML:
+//
+//	level: info
+//	caller: true
+//	tag_levels:
+//	  Database: trace
+//	  http: warn
+//	writers:
+//	  - type: pretty
+//	    time_format: "15:04:05"
+//	    styles:
+//	      message:
+//	        foreground: "15"
+//	        bold: true
+//	  - type: file
+//	    path: /var/log/app.log
+//	    max_size: 104857600
+//	    max_backups: 5
+type Config struct {
+	// Level is the minimum level of written events.
+	// Any value accepted by level.LevelFromString is
+	// valid. Defaults to "info".
+	Level string `json:"level" yaml:"level"`
+	// TagLevels maps tag patterns to levels as set
+	// via Logger.SetTagLevel.
+	TagLevels map[string]string `json:"tag_levels" yaml:"tag_levels"`
+	// Caller enables attaching the caller file and
+	// line to events.
+	Caller bool `json:"caller" yaml:"caller"`
+	// Writers specifies the writers of the logger.
+	// Defaults to a single pretty writer.
+	Writers []WriterConfig `json:"writers" yaml:"writers"`
+}
+
+// Load reads the config from the file at the given
+// path. The format is determined by the extension
+// of the file, which must be .json, .yaml or .yml.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FromJSON(data)
+	case ".yaml", ".yml":
+		return FromYAML(data)
+	}
+
+	return nil, fmt.Errorf("config: unsupported file extension %q; valid extensions are .json, .yaml and .yml",
+		filepath.Ext(path))
+}
+
+// FromJSON parses the config from JSON data.
+func FromJSON(data []byte) (*Config, error) {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("config: invalid JSON: %w", err)
+	}
+	return &c, nil
+}
+
+// FromYAML parses the config from YAML data.
+func FromYAML(data []byte) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("config: invalid YAML: %w", err)
+	}
+	return &c, nil
+}
+
+// Validate returns an error describing all invalid
+// values of the config.
+func (t *Config) Validate() error {
+	var errs []error
+
+	if t.Level != "" {
+		if _, ok := level.LevelFromString(t.Level); !ok {
+			errs = append(errs, invalidLevelError("level", t.Level))
+		}
+	}
+
+	for pattern, v := range t.TagLevels {
+		if _, ok := level.LevelFromString(v); !ok {
+			errs = append(errs, invalidLevelError(fmt.Sprintf("tag_levels[%q]", pattern), v))
+		}
+	}
+
+	for i, w := range t.Writers {
+		if err := w.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("writers[%d]: %w", i, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("config: %w", errors.Join(errs...))
+}
+
+// Build validates the config and returns a new
+// rogu.Logger as specified.
+func (t *Config) Build() (rogu.Logger, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	lvl := level.Info
+	if t.Level != "" {
+		lvl, _ = level.LevelFromString(t.Level)
+	}
+
+	tagLevels := make(map[string]level.Level, len(t.TagLevels))
+	for pattern, v := range t.TagLevels {
+		tagLevels[pattern], _ = level.LevelFromString(v)
+	}
+
+	writerConfigs := t.Writers
+	if len(writerConfigs) == 0 {
+		writerConfigs = []WriterConfig{{Type: TypePretty}}
+	}
+
+	writers := make(rogu.MultiWriter, 0, len(writerConfigs))
+	for i, wc := range writerConfigs {
+		w, err := wc.build()
+		if err != nil {
+			// Only the writers of files opened here are
+			// closed, because closing the others would
+			// close stdout or stderr.
+			for j, w := range writers {
+				if c, ok := w.(rogu.Closer); ok && writerConfigs[j].opensFile() {
+					c.Close()
+				}
+			}
+			return nil, fmt.Errorf("config: writers[%d]: %w", i, err)
+		}
+		writers = append(writers, w)
+	}
+
+	var l rogu.Logger
+	if len(writers) == 1 {
+		l = rogu.NewLogger(writers[0])
+	} else {
+		l = rogu.NewLogger(writers)
+	}
+
+	return l.
+		SetLevel(lvl).
+		SetTagLevels(tagLevels).
+		SetCaller(t.Caller), nil
+}
+
+func invalidLevelError(key, v string) error {
+	return fmt.Errorf("%s: invalid level %q; valid levels are off, panic, fatal, error, warn, info, debug, trace, all or 0-8",
+		key, v)
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestFromYAML(t *testing.T) {
+	c, err := FromYAML([]byte(`
+level: debug
+caller: true
+tag_levels:
+  Database: trace
+writers:
+  - type: pretty
+    output: stderr
+    time_format: ""
+    no_color: true
+    styles:
+      message:
+        foreground: "15"
+        bold: true
+  - type: file
+    path: app.log
+    interval: 24h
+    max_backups: 3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Level != "debug" || !c.Caller || c.TagLevels["Database"] != "trace" || len(c.Writers) != 2 {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+
+	w := c.Writers[0]
+	if w.Output != "stderr" || w.TimeFormat == nil || *w.TimeFormat != "" || !w.NoColor ||
+		w.Styles["message"].Foreground != "15" || !*w.Styles["message"].Bold {
+		t.Errorf("unexpected pretty writer config: %+v", w)
+	}
+
+	if w = c.Writers[1]; time.Duration(w.Interval) != 24*time.Hour || w.MaxBackups != 3 {
+		t.Errorf("unexpected file writer config: %+v", w)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	c, err := FromJSON([]byte(`{
+		"level": "warn",
+		"tag_levels": {"Database": "trace"},
+		"writers": [{"type": "file", "path": "` + path + `", "schema": "flat"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := c.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Msg("not written")
+	l.Tagged("Database").Debug().Msg("query")
+	if err = l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(data); strings.Count(out, "\n") != 1 || !strings.Contains(out, `"msg":"query"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	if l.Level() != level.Warn {
+		t.Errorf("unexpected level: %s", l.Level())
+	}
+}
+
+func TestBuildSchemaCopy(t *testing.T) {
+	w, err := WriterConfig{Type: TypeJson, Output: "stdout", Schema: "ecs"}.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jw := w.(*rogu.JsonWriter)
+	if jw.Schema == rogu.JsonSchemaECS || jw.Schema.TimestampKey != "@timestamp" {
+		t.Errorf("writer does not use a copy of the ECS schema")
+	}
+}
+
+func TestBuildFailureKeepsStdout(t *testing.T) {
+	c := Config{Writers: []WriterConfig{
+		{Type: TypeJson},
+		{Type: TypeLogfmt, Output: "/nonexistent/app.log"},
+	}}
+	if _, err := c.Build(); err == nil {
+		t.Fatal("expected error for invalid output")
+	}
+	if _, err := os.Stdout.Stat(); err != nil {
+		t.Errorf("stdout has been closed: %s", err)
+	}
+}
+
+func TestBuildPrettyWithoutTimestamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	c, err := FromJSON([]byte(`{
+		"level": "all",
+		"tag_levels": {"Database": "off"},
+		"writers": [{"type": "pretty", "output": "` + path + `", "time_format": "", "no_color": true}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := c.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	l.Trace().Time("at", tm).Msg("time")
+	l.Tagged("Database").Error().Msg("not written")
+	if err = l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(data); strings.Count(out, "\n") != 1 ||
+		!strings.HasPrefix(out, "TRACE") || !strings.Contains(out, tm.Format(time.RFC3339Nano)) {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := Config{
+		Level:     "loud",
+		TagLevels: map[string]string{"Database": "verbose"},
+		Writers: []WriterConfig{
+			{Type: "xml"},
+			{Type: TypeFile},
+			{Type: TypePretty, Styles: map[string]StyleConfig{"sparkles": {}}},
+			{Type: TypeJson, Schema: "splunk"},
+		},
+	}
+
+	_, err := c.Build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, s := range []string{
+		`level: invalid level "loud"`,
+		`tag_levels["Database"]: invalid level "verbose"`,
+		`writers[0]: type: unknown type "xml"`,
+		`writers[1]: path: must be set`,
+		`writers[2]: styles: unknown style "sparkles"`,
+		`writers[3]: schema: unknown schema "splunk"`,
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error does not contain %q:\n%s", s, err)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("APP_LOG_LEVEL", "trace")
+	t.Setenv("APP_LOG_TAG_LEVELS", "Database=debug, http=warn")
+	t.Setenv("APP_LOG_CALLER", "true")
+	t.Setenv("APP_LOG_FORMAT", "JSON")
+	t.Setenv("APP_LOG_OUTPUT", "stderr")
+	t.Setenv("APP_LOG_SCHEMA", "ecs")
+
+	c, err := FromEnv("APP_LOG")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Level != "trace" || !c.Caller || len(c.TagLevels) != 2 || c.TagLevels["http"] != "warn" {
+		t.Errorf("unexpected config: %+v", c)
+	}
+	if len(c.Writers) != 1 || c.Writers[0].Type != TypeJson ||
+		c.Writers[0].Output != "stderr" || c.Writers[0].Schema != "ecs" {
+		t.Errorf("unexpected writers: %+v", c.Writers)
+	}
+
+	if _, err = c.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("APP_LOG_CALLER", "maybe")
+	t.Setenv("APP_LOG_TAG_LEVELS", "Database")
+	if _, err = FromEnv("APP_LOG"); err == nil ||
+		!strings.Contains(err.Error(), "APP_LOG_CALLER") ||
+		!strings.Contains(err.Error(), "APP_LOG_TAG_LEVELS") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultEnvPrefix is the prefix of environment
+// variables used when no prefix is passed to
+// FromEnv or ApplyEnv.
+const DefaultEnvPrefix = "LOG"
+
+// FromEnv returns a new config read from the
+// environment variables with the given prefix.
+// See ApplyEnv for the supported variables.
+func FromEnv(prefix string) (*Config, error) {
+	var c Config
+	if err := c.ApplyEnv(prefix); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ApplyEnv overrides the values of the config with
+// the values of the following environment variables,
+// if set. The names are shown with the default
+// prefix "LOG".
+//
+//	LOG_LEVEL        level
+//	LOG_TAG_LEVELS   tag levels like "Database=trace,http=warn"
+//	LOG_CALLER       caller as boolean like "true" or "1"
+//	LOG_FORMAT       replaces the writers with a single writer
+//	                 of the given type: pretty, json or logfmt
+//	LOG_OUTPUT       output of all non-file writers
+//	LOG_TIME_FORMAT  time format of all writers
+//	LOG_NO_COLOR     disables colors of pretty writers
+//	LOG_SCHEMA       schema of all json and file writers
+//	LOG_FILE         adds a file writer with the given path
+func (t *Config) ApplyEnv(prefix string) error {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	env := func(name string) (string, bool) {
+		return os.LookupEnv(prefix + "_" + name)
+	}
+
+	var errs []error
+
+	if v, ok := env("LEVEL"); ok {
+		t.Level = v
+	}
+
+	if v, ok := env("TAG_LEVELS"); ok {
+		tagLevels, err := parseTagLevels(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_TAG_LEVELS: %w", prefix, err))
+		}
+		t.TagLevels = tagLevels
+	}
+
+	if v, ok := env("CALLER"); ok {
+		caller, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_CALLER: invalid boolean %q", prefix, v))
+		}
+		t.Caller = caller
+	}
+
+	if v, ok := env("FORMAT"); ok {
+		t.Writers = []WriterConfig{{Type: strings.ToLower(v)}}
+	}
+
+	if len(t.Writers) == 0 {
+		// Writer options apply to the default
+		// pretty writer when no writers are set.
+		for _, name := range []string{"FILE", "OUTPUT", "TIME_FORMAT", "NO_COLOR", "SCHEMA"} {
+			if _, ok := env(name); ok {
+				t.Writers = []WriterConfig{{Type: TypePretty}}
+				break
+			}
+		}
+	}
+
+	if v, ok := env("FILE"); ok && v != "" {
+		t.Writers = append(t.Writers, WriterConfig{Type: TypeFile, Path: v})
+	}
+
+	for i := range t.Writers {
+		w := &t.Writers[i]
+		if v, ok := env("OUTPUT"); ok && w.Type != TypeFile {
+			w.Output = v
+		}
+		if v, ok := env("TIME_FORMAT"); ok {
+			w.TimeFormat = &v
+		}
+		if v, ok := env("NO_COLOR"); ok {
+			noColor, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s_NO_COLOR: invalid boolean %q", prefix, v))
+			}
+			w.NoColor = noColor
+		}
+		if v, ok := env("SCHEMA"); ok {
+			w.Schema = v
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("config: %w", errors.Join(errs...))
+}
+
+func parseTagLevels(v string) (map[string]string, error) {
+	tagLevels := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tag, lvl, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tag level %q; must be formatted like \"tag=level\"", pair)
+		}
+		tagLevels[strings.TrimSpace(tag)] = strings.TrimSpace(lvl)
+	}
+	return tagLevels, nil
+}
@@ -0,0 +1,338 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zekrotja/rogu"
+	"gopkg.in/yaml.v3"
+)
+
+// Writer types which can be specified in
+// WriterConfig.Type.
+const (
+	TypePretty = "pretty"
+	TypeJson   = "json"
+	TypeLogfmt = "logfmt"
+	TypeFile   = "file"
+)
+
+// Outputs which can be specified in
+// WriterConfig.Output besides file paths.
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// WriterConfig specifies a single writer.
+type WriterConfig struct {
+	// Type is one of "pretty", "json", "logfmt"
+	// or "file".
+	Type string `json:"type" yaml:"type"`
+	// Output is "stdout", "stderr" or the path of
+	// a file the output is appended to. Defaults to
+	// "stdout". Not used for the "file" type.
+	Output string `json:"output" yaml:"output"`
+	// TimeFormat is the format of timestamps. When
+	// set to an empty string, the timestamp of the
+	// entry is not written and time fields are
+	// written in RFC 3339 format. Defaults to the
+	// default of the writer.
+	TimeFormat *string `json:"time_format" yaml:"time_format"`
+
+	// NoColor disables colorful output of the
+	// "pretty" writer.
+	NoColor bool `json:"no_color" yaml:"no_color"`
+	// Styles overrides styles of the "pretty"
+	// writer. Valid keys are the names of the style
+	// fields of rogu.PrettyWriter in snake case
+	// without the "Style" prefix, like "level_info"
+	// or "field_key".
+	Styles map[string]StyleConfig `json:"styles" yaml:"styles"`
+
+	// Schema is the JSON schema of the "json" and
+	// "file" writers. One of "default", "flat", "ecs"
+	// or "gcp".
+	Schema string `json:"schema" yaml:"schema"`
+
+	// Path is the path of the file written by the
+	// "file" writer.
+	Path string `json:"path" yaml:"path"`
+	// MaxSize is the maximum size of the file in
+	// bytes before it is rotated.
+	MaxSize int64 `json:"max_size" yaml:"max_size"`
+	// Interval is the duration after which the
+	// file is rotated like "24h".
+	Interval Duration `json:"interval" yaml:"interval"`
+	// MaxBackups is the maximum number of rotated
+	// files which are kept.
+	MaxBackups int `json:"max_backups" yaml:"max_backups"`
+	// Compress enables gzip compression of
+	// rotated files.
+	Compress bool `json:"compress" yaml:"compress"`
+}
+
+// StyleConfig overrides properties of a
+// style of the PrettyWriter.
+type StyleConfig struct {
+	// Foreground and Background are colors like
+	// "196" (ANSI 256) or "#ff0000".
+	Foreground string `json:"foreground" yaml:"foreground"`
+	Background string `json:"background" yaml:"background"`
+	Bold       *bool  `json:"bold" yaml:"bold"`
+	Italic     *bool  `json:"italic" yaml:"italic"`
+	Underline  *bool  `json:"underline" yaml:"underline"`
+	Faint      *bool  `json:"faint" yaml:"faint"`
+	Width      *int   `json:"width" yaml:"width"`
+}
+
+// Duration is a time.Duration which is
+// unmarshaled from strings like "1h30m".
+type Duration time.Duration
+
+func (t *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"24h\": %w", err)
+	}
+	return t.parse(s)
+}
+
+func (t *Duration) UnmarshalYAML(node *yaml.Node) error {
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return err
+	}
+	return t.parse(s)
+}
+
+func (t *Duration) parse(s string) error {
+	if s == "" {
+		*t = 0
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*t = Duration(d)
+	return nil
+}
+
+// schema returns a copy of the predefined schema
+// with the given name, so that the writers do not
+// share the schema.
+func schema(name string) *rogu.JsonSchema {
+	s := *schemas[strings.ToLower(name)]
+	return &s
+}
+
+var schemas = map[string]*rogu.JsonSchema{
+	"":        rogu.JsonSchemaDefault,
+	"default": rogu.JsonSchemaDefault,
+	"flat":    rogu.JsonSchemaFlat,
+	"ecs":     rogu.JsonSchemaECS,
+	"gcp":     rogu.JsonSchemaGCP,
+}
+
+func (t WriterConfig) validate() error {
+	var errs []error
+
+	switch t.Type {
+	case TypePretty, TypeJson, TypeLogfmt:
+	case TypeFile:
+		if t.Path == "" {
+			errs = append(errs, errors.New("path: must be set for the file writer"))
+		}
+	case "":
+		errs = append(errs, errors.New("type: must be set; valid types are pretty, json, logfmt and file"))
+	default:
+		errs = append(errs, fmt.Errorf("type: unknown type %q; valid types are pretty, json, logfmt and file", t.Type))
+	}
+
+	if _, ok := schemas[strings.ToLower(t.Schema)]; !ok {
+		errs = append(errs, fmt.Errorf("schema: unknown schema %q; valid schemas are default, flat, ecs and gcp", t.Schema))
+	}
+
+	if len(t.Styles) > 0 {
+		var pw rogu.PrettyWriter
+		for name := range t.Styles {
+			if prettyStyle(&pw, name) == nil {
+				errs = append(errs, fmt.Errorf("styles: unknown style %q", name))
+			}
+		}
+	}
+
+	if t.MaxSize < 0 {
+		errs = append(errs, errors.New("max_size: must not be negative"))
+	}
+	if t.MaxBackups < 0 {
+		errs = append(errs, errors.New("max_backups: must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t WriterConfig) build() (rogu.Writer, error) {
+	if t.Type == TypeFile {
+		w, err := rogu.NewFileWriter(t.Path, rogu.RotateOptions{
+			MaxSize:    t.MaxSize,
+			Interval:   time.Duration(t.Interval),
+			MaxBackups: t.MaxBackups,
+			Compress:   t.Compress,
+		})
+		if err != nil {
+			return nil, err
+		}
+		w.Schema = schema(t.Schema)
+		if t.TimeFormat != nil {
+			w.TimeFormat = *t.TimeFormat
+		}
+		return w, nil
+	}
+
+	output, err := t.output()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Type {
+	case TypeJson:
+		w := rogu.NewJsonWriter(output)
+		w.Schema = schema(t.Schema)
+		if t.TimeFormat != nil {
+			w.TimeFormat = *t.TimeFormat
+		}
+		return w, nil
+
+	case TypeLogfmt:
+		w := rogu.NewLogfmtWriter(output)
+		if t.TimeFormat != nil {
+			w.TimeFormat = *t.TimeFormat
+		}
+		return w, nil
+	}
+
+	w := rogu.NewPrettyWriter(output)
+	w.NoColor = t.NoColor
+	if t.TimeFormat != nil {
+		w.TimeFormat = *t.TimeFormat
+	}
+	for name, sc := range t.Styles {
+		s := prettyStyle(w, name)
+		*s = sc.apply(*s)
+	}
+	return w, nil
+}
+
+// opensFile returns true if the writer writes to
+// a file opened when it is built.
+func (t WriterConfig) opensFile() bool {
+	if t.Type == TypeFile {
+		return true
+	}
+	switch strings.ToLower(t.Output) {
+	case "", OutputStdout, OutputStderr:
+		return false
+	}
+	return true
+}
+
+func (t WriterConfig) output() (io.Writer, error) {
+	switch strings.ToLower(t.Output) {
+	case "", OutputStdout:
+		return os.Stdout, nil
+	case OutputStderr:
+		return os.Stderr, nil
+	}
+
+	f, err := os.OpenFile(t.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	return f, nil
+}
+
+func (t StyleConfig) apply(s lipgloss.Style) lipgloss.Style {
+	if t.Foreground != "" {
+		s = s.Foreground(lipgloss.Color(t.Foreground))
+	}
+	if t.Background != "" {
+		s = s.Background(lipgloss.Color(t.Background))
+	}
+	if t.Bold != nil {
+		s = s.Bold(*t.Bold)
+	}
+	if t.Italic != nil {
+		s = s.Italic(*t.Italic)
+	}
+	if t.Underline != nil {
+		s = s.Underline(*t.Underline)
+	}
+	if t.Faint != nil {
+		s = s.Faint(*t.Faint)
+	}
+	if t.Width != nil {
+		s = s.Width(*t.Width)
+	}
+	return s
+}
+
+// prettyStyle returns a pointer to the style of w
+// with the given name or nil if no style with the
+// name exists.
+func prettyStyle(w *rogu.PrettyWriter, name string) *lipgloss.Style {
+	switch strings.ToLower(name) {
+	case "timestamp":
+		return &w.StyleTimestamp
+	case "level_panic":
+		return &w.StyleLevelPanic
+	case "level_fatal":
+		return &w.StyleLevelFatal
+	case "level_error":
+		return &w.StyleLevelError
+	case "level_warn":
+		return &w.StyleLevelWarn
+	case "level_info":
+		return &w.StyleLevelInfo
+	case "level_debug":
+		return &w.StyleLevelDebug
+	case "level_trace":
+		return &w.StyleLevelTrace
+	case "caller":
+		return &w.StyleCaller
+	case "tag":
+		return &w.StyleTag
+	case "field_key":
+		return &w.StyleFieldKey
+	case "field_value":
+		return &w.StyleFieldValue
+	case "field_multiple_key":
+		return &w.StyleFieldMultipleKey
+	case "field_multiple_index":
+		return &w.StyleFieldMultipleIndex
+	case "field_multiple_value":
+		return &w.StyleFieldMultipleValue
+	case "field_error_key":
+		return &w.StyleFieldErrorKey
+	case "field_error_value":
+		return &w.StyleFieldErrorValue
+	case "message":
+		return &w.StyleMessage
+	case "error_tree":
+		return &w.StyleErrorTree
+	case "error_cause":
+		return &w.StyleErrorCause
+	case "stack_function":
+		return &w.StyleStackFunction
+	case "stack_location":
+		return &w.StyleStackLocation
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package rogu
+
+import (
+	"context"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type loggerCtxKey struct{}
+
+// ContextExtractor takes a context and returns
+// alternating keys and values which are added
+// as fields to an event which has been passed
+// the context via `Event.Ctx`.
+//
+// Example:
+//
+//	l.AddContextExtractor(func(ctx context.Context) []any {
+//	    id, ok := ctx.Value(requestIdKey{}).(string)
+//	    if !ok {
+//	        return nil
+//	    }
+//	    return []any{"request_id", id}
+//	})
+type ContextExtractor func(ctx context.Context) []any
+
+// WithContext returns a copy of ctx which
+// carries the given Logger.
+func WithContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx
+// via `WithContext`.
+//
+// If no Logger is stored in ctx, a shared
+// disabled Logger is returned which will never
+// output anything. It must not be modified.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(Logger); ok {
+		return l
+	}
+	return disabledLogger
+}
+
+// disabledLogger is returned by FromContext so
+// that no Logger is created on every call.
+var disabledLogger = NewLogger().SetLevel(level.Off)
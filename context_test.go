@@ -0,0 +1,134 @@
+package rogu
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type requestIdKey struct{}
+
+func TestContextLogger(t *testing.T) {
+	var w recordWriter
+	l := NewLogger(&w)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		written int
+	}{
+		{"stored", WithContext(context.Background(), l), 1},
+		{"stored tagged", WithContext(context.Background(), l.Tagged("tag")), 1},
+		{"not stored", context.Background(), 0},
+		{"other value", context.WithValue(context.Background(), loggerCtxKey{}, "nope"), 0},
+	}
+
+	for _, tt := range tests {
+		w.records = nil
+
+		lg := FromContext(tt.ctx)
+		if lg == nil {
+			t.Fatalf("%s: FromContext returned nil", tt.name)
+		}
+		lg.Error().Msg("hello")
+
+		if len(w.records) != tt.written {
+			t.Errorf("%s: %d entries written; want %d", tt.name, len(w.records), tt.written)
+		}
+	}
+
+	if lvl := FromContext(context.Background()).Level(); lvl != level.Off {
+		t.Errorf("fallback logger has level %s; want off", lvl)
+	}
+	if FromContext(context.Background()) != FromContext(context.TODO()) {
+		t.Error("fallback logger is created on every call")
+	}
+}
+
+func TestContextExtractors(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	var order []int
+	l.AddContextExtractor(func(ctx context.Context) []any {
+		order = append(order, 1)
+		id, ok := ctx.Value(requestIdKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return []any{"request_id", id}
+	})
+	l.AddContextExtractor(func(ctx context.Context) []any {
+		order = append(order, 2)
+		return []any{"second", true}
+	})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		keys   []string
+		called bool
+	}{
+		{"value", context.WithValue(context.Background(), requestIdKey{}, "abc"), []string{"k", "request_id", "second"}, true},
+		{"no value", context.Background(), []string{"k", "second"}, true},
+		{"nil", nil, []string{"k"}, false},
+	}
+
+	for _, tt := range tests {
+		w.entries = nil
+		order = nil
+
+		l.Info().Ctx(tt.ctx).Str("k", "v").Msg("hello")
+
+		var keys []string
+		for _, f := range w.entries[0].Fields {
+			keys = append(keys, f.KeyString())
+		}
+		if !slices.Equal(keys, tt.keys) {
+			t.Errorf("%s: unexpected fields %v; want %v", tt.name, keys, tt.keys)
+		}
+
+		if called := len(order) > 0; called != tt.called {
+			t.Errorf("%s: extractors called: %t; want %t", tt.name, called, tt.called)
+		} else if called && !slices.Equal(order, []int{1, 2}) {
+			t.Errorf("%s: extractors applied in order %v; want [1 2]", tt.name, order)
+		}
+	}
+}
+
+// failOnceWriter fails the first write and records
+// the entries of all further writes.
+type failOnceWriter struct {
+	entryWriter
+	failed bool
+}
+
+func (t *failOnceWriter) Write(e Entry) error {
+	if !t.failed {
+		t.failed = true
+		return errors.New("write failed")
+	}
+	return t.entryWriter.Write(e)
+}
+
+func TestContextExtractorsRetry(t *testing.T) {
+	var w failOnceWriter
+	l := NewLogger(&w).AddContextExtractor(func(ctx context.Context) []any {
+		return []any{"rid", ctx.Value(requestIdKey{})}
+	})
+
+	e := l.Info().Ctx(context.WithValue(context.Background(), requestIdKey{}, "abc")).Str("k", "v")
+	if err := e.Msg("hello"); err == nil {
+		t.Fatal("expected first write to fail")
+	}
+	if err := e.Msg("hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fieldsString(w.entries[0]); got != "k=v rid=abc " {
+		t.Errorf("unexpected fields of retried event: %s", got)
+	}
+}
@@ -0,0 +1,93 @@
+package rogu
+
+import "strings"
+
+// maxErrorDepth limits the depth up to which
+// error chains are walked.
+const maxErrorDepth = 32
+
+// FieldsError is implemented by errors which
+// expose structured fields. When the error of an
+// event or any error it wraps implements
+// FieldsError, the fields are added to the event
+// when it is commited.
+//
+// The returned values are interpreted as
+// alternating keys and values like in
+// `Event.Fields`.
+type FieldsError interface {
+	error
+	LogFields() []any
+}
+
+// unwrapErrors returns the errors directly
+// wrapped by err via `Unwrap() error` or
+// `Unwrap() []error`.
+func unwrapErrors(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if c := u.Unwrap(); c != nil {
+			return []error{c}
+		}
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// walkErrors calls fn for err and all errors
+// it wraps in depth-first order.
+func walkErrors(err error, fn func(err error)) {
+	walkErrorsDepth(err, fn, 0)
+}
+
+func walkErrorsDepth(err error, fn func(err error), depth int) {
+	if err == nil || depth > maxErrorDepth {
+		return
+	}
+	fn(err)
+	for _, c := range unwrapErrors(err) {
+		walkErrorsDepth(c, fn, depth+1)
+	}
+}
+
+// errorFields returns the fields of all errors
+// in the chain of err implementing FieldsError.
+func errorFields(err error) (kv []any) {
+	walkErrors(err, func(err error) {
+		if fe, ok := err.(FieldsError); ok {
+			kv = append(kv, fe.LogFields()...)
+		}
+	})
+	return kv
+}
+
+// ownErrorMessage returns the part of the message
+// of err which is not part of the messages of
+// the given causes. For errors created via
+// `fmt.Errorf("read config: %w", err)`, this is
+// "read config". For errors joined via
+// `errors.Join`, this is an empty string.
+func ownErrorMessage(err error, causes []error) string {
+	msg := err.Error()
+
+	switch len(causes) {
+	case 0:
+		return msg
+	case 1:
+		cmsg := causes[0].Error()
+		if cmsg != "" && len(msg) > len(cmsg) && strings.HasSuffix(msg, cmsg) {
+			return strings.TrimRight(strings.TrimSuffix(msg, cmsg), ": ")
+		}
+		return msg
+	}
+
+	msgs := make([]string, len(causes))
+	for i, c := range causes {
+		msgs[i] = c.Error()
+	}
+	if msg == strings.Join(msgs, "\n") {
+		return ""
+	}
+	return msg
+}
@@ -0,0 +1,179 @@
+package rogu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fieldsError struct {
+	id int
+}
+
+func (t fieldsError) Error() string {
+	return "not found"
+}
+
+func (t fieldsError) LogFields() []any {
+	return []any{"id", t.id}
+}
+
+func TestOwnErrorMessage(t *testing.T) {
+	base := errors.New("permission denied")
+	wrapped := fmt.Errorf("read config: %w", base)
+	joined := errors.Join(wrapped, errors.New("other"))
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{base, "permission denied"},
+		{wrapped, "read config"},
+		{joined, ""},
+		{fmt.Errorf("%w", base), "permission denied"},
+		{fmt.Errorf("failed: %w (retrying)", base), "failed: permission denied (retrying)"},
+	}
+
+	for _, tt := range tests {
+		if got := ownErrorMessage(tt.err, unwrapErrors(tt.err)); got != tt.want {
+			t.Errorf("ownErrorMessage(%q) = %q; want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	err := fmt.Errorf("get user: %w", fieldsError{id: 42})
+	l.Error().Err(err).Str("k", "v").Msg("failed")
+
+	fields := w.entries[0].Fields
+	if len(fields) != 2 || fields[0].Key != "k" || fields[1].Key != "id" || fields[1].Val != 42 {
+		t.Errorf("unexpected fields: %+v", fields)
+	}
+}
+
+func TestErrorFieldsRetry(t *testing.T) {
+	var w failOnceWriter
+	l := NewLogger(&w)
+
+	e := l.Error().
+		Err(fieldsError{id: 1}).
+		NamedErr("db", fieldsError{id: 2}).
+		Str("k", "v")
+	if err := e.Msg("failed"); err == nil {
+		t.Fatal("expected first write to fail")
+	}
+	if err := e.Msg("failed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fieldsString(w.entries[0]); got != "k=v id=1 id=2 " {
+		t.Errorf("unexpected fields of retried event: %s", got)
+	}
+}
+
+func TestJsonWriterErrorCauses(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(NewJsonWriter(&buf))
+
+	err := fmt.Errorf("load: %w", errors.Join(errors.New("a"), fmt.Errorf("b: %w", errors.New("c"))))
+	l.Error().Err(err).Msg("failed")
+
+	type cause struct {
+		Message string  `json:"message"`
+		Type    string  `json:"type"`
+		Causes  []cause `json:"causes"`
+	}
+	var res struct {
+		Error  string  `json:"error"`
+		Causes []cause `json:"error.causes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+
+	if len(res.Causes) != 1 || len(res.Causes[0].Causes) != 2 {
+		t.Fatalf("unexpected causes: %+v", res.Causes)
+	}
+	if b := res.Causes[0].Causes[1]; b.Message != "b: c" || b.Type != "*fmt.wrapError" ||
+		len(b.Causes) != 1 || b.Causes[0].Message != "c" {
+		t.Errorf("unexpected cause: %+v", b)
+	}
+}
+
+func TestPrettyWriterErrorTree(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPrettyWriter(&buf)
+	w.NoColor = true
+	w.TimeFormat = ""
+	l := NewLogger(w)
+
+	err := fmt.Errorf("load: %w", errors.Join(fmt.Errorf("read: %w", errors.New("denied")), errors.New("invalid")))
+	l.Error().Err(err).Msg("failed")
+
+	want := strings.Join([]string{
+		"    load",
+		"    ├─ read",
+		"    │  └─ denied",
+		"    └─ invalid",
+	}, "\n")
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("missing error tree in output:\n%s", buf.String())
+	}
+	if strings.Count(buf.String(), "\n") != 5 {
+		t.Errorf("unexpected number of lines:\n%s", buf.String())
+	}
+}
+
+func TestJsonWriterNamedErrorCauses(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(NewJsonWriter(&buf))
+
+	l.Error().
+		NamedErr("db", fmt.Errorf("query: %w", errors.New("timeout"))).
+		NamedErr("cache", errors.New("miss")).
+		Msg("failed")
+
+	var res map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+
+	causes, _ := res["error.db.causes"].([]any)
+	if res["error.db"] != "query: timeout" || len(causes) != 1 ||
+		causes[0].(map[string]any)["message"] != "timeout" {
+		t.Errorf("unexpected named error: %s", buf.String())
+	}
+	if _, ok := res["error.cache.causes"]; ok || res["error.cache"] != "miss" {
+		t.Errorf("unexpected named error without causes: %s", buf.String())
+	}
+}
+
+func TestPrettyWriterNamedErrorTree(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewPrettyWriter(&buf)
+	w.NoColor = true
+	w.TimeFormat = ""
+	l := NewLogger(w)
+
+	l.Error().
+		Err(fmt.Errorf("load: %w", errors.New("denied"))).
+		NamedErr("db", fmt.Errorf("query: %w", errors.New("timeout"))).
+		Msg("failed")
+
+	want := strings.Join([]string{
+		"    load",
+		"    └─ denied",
+		"error.db:",
+		"    query",
+		"    └─ timeout",
+	}, "\n")
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("missing error trees in output:\n%s", buf.String())
+	}
+}
@@ -1,8 +1,12 @@
 package rogu
 
 import (
+	"context"
 	"fmt"
-	"reflect"
+	"math"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/zekrotja/rogu/level"
 )
@@ -17,19 +21,6 @@ var fieldsPool = newSafePool(func() *Field {
 	return &Field{}
 })
 
-// Field hols a hey-value pair of any type.
-type Field struct {
-	Key any
-	Val any
-
-	valueKind reflect.Kind
-}
-
-func (t *Field) Reset() {
-	t.Key = nil
-	t.Val = nil
-}
-
 // Event is used to build and send
 // log messages.
 type Event struct {
@@ -38,7 +29,16 @@ type Event struct {
 	tag       string
 	err       error
 	errFormat string
+	errs      []NamedError
+	errN      int
 	caller    bool
+	stack     bool
+	exitCode  int
+	ctx       context.Context
+
+	time       time.Time
+	callerFile string
+	callerLine int
 
 	l eventWriter
 }
@@ -49,12 +49,24 @@ func (t *Event) Reset() {
 	t.lvl = 0
 	t.tag = ""
 	t.err = nil
+	t.errFormat = ""
+	t.errs = t.errs[:0]
+	t.errN = 0
+	t.caller = false
+	t.stack = false
+	t.exitCode = 0
+	t.ctx = nil
+	t.time = time.Time{}
+	t.callerFile = ""
+	t.callerLine = 0
 }
 
 func newEvent(l eventWriter, lvl level.Level) *Event {
 	e := eventPool.Get()
 	e.l = l
 	e.lvl = lvl
+	e.exitCode = 1
+	e.time = time.Now()
 	return e
 }
 
@@ -115,17 +127,215 @@ func (t *Event) Field(key, value any) *Event {
 	return t
 }
 
+// Str adds a string field.
+func (t *Event) Str(key, v string) *Event {
+	f := t.newField(key, KindString)
+	f.str = v
+	return t
+}
+
+// Int adds an int field.
+func (t *Event) Int(key string, v int) *Event {
+	return t.Int64(key, int64(v))
+}
+
+// Int64 adds an int64 field.
+func (t *Event) Int64(key string, v int64) *Event {
+	f := t.newField(key, KindInt64)
+	f.num = uint64(v)
+	return t
+}
+
+// Uint adds an uint field.
+func (t *Event) Uint(key string, v uint) *Event {
+	f := t.newField(key, KindUint64)
+	f.num = uint64(v)
+	return t
+}
+
+// Float adds a float64 field.
+func (t *Event) Float(key string, v float64) *Event {
+	f := t.newField(key, KindFloat64)
+	f.num = math.Float64bits(v)
+	return t
+}
+
+// Bool adds a bool field.
+func (t *Event) Bool(key string, v bool) *Event {
+	f := t.newField(key, KindBool)
+	if v {
+		f.num = 1
+	}
+	return t
+}
+
+// Dur adds a time.Duration field.
+func (t *Event) Dur(key string, v time.Duration) *Event {
+	f := t.newField(key, KindDuration)
+	f.num = uint64(v)
+	return t
+}
+
+// Time adds a time.Time field.
+func (t *Event) Time(key string, v time.Time) *Event {
+	f := t.newField(key, KindTime)
+	f.tm = v
+	return t
+}
+
+// Bytes adds a byte slice field which is
+// written as string.
+func (t *Event) Bytes(key string, v []byte) *Event {
+	f := t.newField(key, KindBytes)
+	f.byts = v
+	return t
+}
+
+// Strs adds a string slice field.
+func (t *Event) Strs(key string, v []string) *Event {
+	f := t.newField(key, KindStrings)
+	f.strs = v
+	return t
+}
+
+// Ints adds an int slice field.
+func (t *Event) Ints(key string, v []int) *Event {
+	f := t.newField(key, KindInts)
+	f.ints = v
+	return t
+}
+
+// Stringer adds a field with the value of
+// v.String(), which is only called when the
+// event is written.
+func (t *Event) Stringer(key string, v fmt.Stringer) *Event {
+	f := t.newField(key, KindStringer)
+	f.Val = v
+	return t
+}
+
+// Object adds a field with a value which is
+// written as structured object, for example
+// a struct or a map.
+func (t *Event) Object(key string, v any) *Event {
+	f := t.newField(key, KindObject)
+	f.Val = v
+	return t
+}
+
+func (t *Event) newField(key string, kind FieldKind) *Field {
+	f := fieldsPool.Get()
+	f.Key = boxKey(key)
+	f.kind = kind
+	t.fields = append(t.fields, f)
+	return f
+}
+
 // Err sets an error value to the event.
+//
+// When the event already has an error, err is
+// added as additional error like via `Errs`.
+// Nil errors are ignored.
 func (t *Event) Err(err error) *Event {
-	t.err = err
-	return t
+	return t.addErr("", err, "")
 }
 
 // Errf sets an error value to the event formatted in the
 // given format string.
+//
+// When the event already has an error, err is
+// added as additional error like via `Errs`.
+// Nil errors are ignored.
 func (t *Event) Errf(err error, format string) *Event {
-	t.err = err
-	t.errFormat = format
+	return t.addErr("", err, format)
+}
+
+// NamedErr adds an additional error with the given
+// name to the event, which is written like a field
+// with the key `error.<name>`. When the name is
+// already taken by another error of the event, it
+// is suffixed with a number like `db_2`.
+//
+// Example:
+//
+//	rogu.Error().
+//	    NamedErr("db", dbErr).
+//	    NamedErr("cache", cacheErr).
+//	    Msg("Failed to fetch user")
+func (t *Event) NamedErr(name string, err error) *Event {
+	return t.addErr(name, err, "")
+}
+
+// Errs adds the given errors to the event. The
+// first error is set as error of the event if it
+// has none yet. The others are added as additional
+// errors numbered in the order they are added like
+// `error.1`, independent of errors added via
+// `NamedErr`. Nil errors are ignored.
+func (t *Event) Errs(errs ...error) *Event {
+	for _, err := range errs {
+		t.addErr("", err, "")
+	}
+	return t
+}
+
+func (t *Event) addErr(name string, err error, format string) *Event {
+	if err == nil {
+		return t
+	}
+	if name == "" {
+		if t.err == nil {
+			t.err = err
+			t.errFormat = format
+			return t
+		}
+		t.errN++
+		name = strconv.Itoa(t.errN)
+	}
+	t.errs = append(t.errs, NamedError{Name: t.uniqueErrName(name), Err: err, Format: format})
+	return t
+}
+
+// reservedErrNames are the names which would
+// collide with the keys of the error of the event
+// in the built-in JSON schemas.
+var reservedErrNames = []string{"causes", "message", "stack_trace", "type"}
+
+// uniqueErrName returns name or, when it is
+// reserved or already taken by another error of
+// the event, name suffixed with the lowest free
+// number starting at 2.
+func (t *Event) uniqueErrName(name string) string {
+	if !t.errNameTaken(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		if n := name + "_" + strconv.Itoa(i); !t.errNameTaken(n) {
+			return n
+		}
+	}
+}
+
+func (t *Event) errNameTaken(name string) bool {
+	if slices.Contains(reservedErrNames, name) {
+		return true
+	}
+	for _, ne := range t.errs {
+		if ne.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Ctx sets the context of the event.
+//
+// When the event is commited, all ContextExtractors
+// registered to the logger are applied to the
+// context and the extracted fields are added to
+// the event.
+func (t *Event) Ctx(ctx context.Context) *Event {
+	t.ctx = ctx
 	return t
 }
 
@@ -136,10 +346,47 @@ func (t *Event) Caller() *Event {
 	return t
 }
 
+// Stack adds the stack trace of the current
+// goroutine to the event. When the error of the
+// event carries a stack trace (see
+// `Logger.SetStackLevel`), that one is used
+// instead.
+func (t *Event) Stack() *Event {
+	t.stack = true
+	return t
+}
+
+// ExitCode sets the code the program exits with
+// when the event has the level fatal. Defaults
+// to 1.
+func (t *Event) ExitCode(code int) *Event {
+	t.exitCode = code
+	return t
+}
+
 // Msg commits the event to the writer with
 // the given message string returning an
 // error when the log writing failed.
 func (t *Event) Msg(v string) error {
+	return t.commit(v)
+}
+
+// Msgf is an alias for Msg with a format and
+// given values.
+func (t *Event) Msgf(format string, args ...any) error {
+	return t.commit(fmt.Sprintf(format, args...))
+}
+
+// Send commits the event without any message.
+func (t *Event) Send() error {
+	return t.commit("")
+}
+
+// commit passes the event to the logger. It must
+// only be called directly by the exported commit
+// methods so that the call depth to the caller
+// stays the same.
+func (t *Event) commit(v string) error {
 	if t.l == nil {
 		return nil
 	}
@@ -154,17 +401,6 @@ func (t *Event) Msg(v string) error {
 	return err
 }
 
-// Msgf is an alias for Msg with a format and
-// given values.
-func (t *Event) Msgf(format string, args ...any) error {
-	return t.Msg(fmt.Sprintf(format, args...))
-}
-
-// Send commits the event without any message.
-func (t *Event) Send() error {
-	return t.Msg("")
-}
-
 // Discard simply throws away the event and gives
 // back the used resources.
 //
@@ -0,0 +1,160 @@
+package rogu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type nopWriter struct{}
+
+func (nopWriter) Write(Entry) error { return nil }
+
+func TestTypedFields(t *testing.T) {
+	var w recordWriter
+	l := NewLogger(&w)
+
+	now := time.Now()
+	l.Info().
+		Str("str", "foo").
+		Int("int", -1).
+		Int64("int64", 2).
+		Uint("uint", 3).
+		Float("float", 1.5).
+		Bool("bool", true).
+		Dur("dur", time.Second).
+		Time("time", now).
+		Bytes("bytes", []byte("bar")).
+		Strs("strs", []string{"a"}).
+		Ints("ints", []int{1}).
+		Msg("typed")
+
+	rec := w.records[0]
+	for k, v := range map[string]any{
+		"str":   "foo",
+		"int":   int64(-1),
+		"int64": int64(2),
+		"uint":  uint64(3),
+		"float": 1.5,
+		"bool":  true,
+		"dur":   time.Second,
+		"time":  now,
+	} {
+		if rec[k] != v {
+			t.Errorf("unexpected value of %s: %v", k, rec[k])
+		}
+	}
+	if string(rec["bytes"].([]byte)) != "bar" {
+		t.Errorf("unexpected value of bytes: %v", rec["bytes"])
+	}
+}
+
+func TestEventErrs(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	errA, errB, errC, errDB := errors.New("a"), errors.New("b"), errors.New("c"), errors.New("db")
+
+	l.Error().
+		Err(errA).
+		Err(errB).
+		NamedErr("db", errDB).
+		Errs(nil, errC).
+		Msg("failed")
+
+	e := w.entries[0]
+	if e.Err != errA {
+		t.Errorf("unexpected error: %v", e.Err)
+	}
+	if len(e.Errs) != 3 || e.Errs[0].Err != errB || e.Errs[1].Name != "db" || e.Errs[2].Err != errC {
+		t.Fatalf("unexpected errors: %+v", e.Errs)
+	}
+	if names := errNames(e.Errs); !slices.Equal(names, []string{"1", "db", "2"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	var pretty, js bytes.Buffer
+	pw := NewPrettyWriter(&pretty)
+	pw.NoColor = true
+	if err := pw.Write(e); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`error="a"`, `error.1="b"`, `error.db="db"`, `error.2="c"`} {
+		if !strings.Contains(pretty.String(), s) {
+			t.Errorf("missing %s in output: %s", s, pretty.String())
+		}
+	}
+
+	if err := NewJsonWriter(&js).Write(e); err != nil {
+		t.Fatal(err)
+	}
+	var res map[string]any
+	if err := json.Unmarshal(js.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %s", js.String(), err)
+	}
+	if res["error"] != "a" || res["error.1"] != "b" || res["error.db"] != "db" || res["error.2"] != "c" {
+		t.Errorf("unexpected errors in output: %s", js.String())
+	}
+}
+
+func TestEventErrNames(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	err := errors.New("err")
+
+	l.Error().
+		Err(err).
+		NamedErr("1", err).
+		Errs(err, err).
+		NamedErr("causes", err).
+		NamedErr("db", err).
+		NamedErr("db", err).
+		NamedErr("db_2", err).
+		Msg("failed")
+
+	want := []string{"1", "1_2", "2", "causes_2", "db", "db_2", "db_2_2"}
+	if names := errNames(w.entries[0].Errs); !slices.Equal(names, want) {
+		t.Errorf("unexpected names: %v; want %v", names, want)
+	}
+}
+
+func errNames(errs []NamedError) []string {
+	names := make([]string, len(errs))
+	for i, ne := range errs {
+		names[i] = ne.Name
+	}
+	return names
+}
+
+func BenchmarkFields(b *testing.B) {
+	l := NewLogger(nopWriter{})
+
+	b.Run("any", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info().
+				Field("str", "str").
+				Field("int", i+1000).
+				Field("float", 1.5).
+				Field("dur", time.Second).
+				Msg("bench")
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info().
+				Str("str", "str").
+				Int("int", i+1000).
+				Float("float", 1.5).
+				Dur("dur", time.Second).
+				Msg("bench")
+		}
+	})
+}
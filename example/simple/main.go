@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"time"
 
 	"github.com/zekrotja/rogu/level"
 	"github.com/zekrotja/rogu/log"
@@ -19,6 +20,11 @@ func main() {
 	log.Debug().Fields(
 		"a_map", map[any]any{"a": 1, 1234: "bar", "bazz": []any{5, 6, 7}},
 	).Msg("Some map fields!")
+	log.Info().
+		Str("user", "Bob").
+		Int("age", 24).
+		Dur("took", 1500*time.Millisecond).
+		Msg("Some typed fields!")
 	log.Error().Err(errors.New("some error")).Msg("Oh no")
 	log.Trace().Caller().Msg("Here")
 
@@ -3,6 +3,7 @@ package main
 import (
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/zekrotja/rogu"
 )
@@ -19,9 +20,12 @@ func main() {
 
 	slog.With("foo", "bar").With("n", 7).Info("hello with inner args")
 
-	dbLogger := slog.Default().WithGroup("Database")
+	dbLogger := slog.Default().With(rogu.TagAttr("Database"))
 	dbLogger.Info("Database initialized")
 
-	wsLogger := slog.Default().WithGroup("WebServer")
+	queryLogger := dbLogger.WithGroup("db").WithGroup("query")
+	queryLogger.Debug("Query executed", "duration", 12*time.Millisecond)
+
+	wsLogger := slog.Default().With(rogu.TagAttr("WebServer"))
 	wsLogger.Error("Failed starting web server", rogu.ErrorAttr(errors.New("invalid host address")))
 }
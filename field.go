@@ -0,0 +1,196 @@
+package rogu
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FieldKind specifies the type of the value
+// stored in a Field.
+type FieldKind uint8
+
+const (
+	// KindAny fields hold an arbitrary value in Val.
+	KindAny FieldKind = iota
+	KindString
+	KindInt64
+	KindUint64
+	KindFloat64
+	KindBool
+	KindDuration
+	KindTime
+	KindBytes
+	KindStrings
+	KindInts
+	// KindStringer fields hold a fmt.Stringer in Val.
+	KindStringer
+	// KindObject fields hold a value in Val which
+	// should be serialized as structured object.
+	KindObject
+)
+
+// Field hols a hey-value pair.
+//
+// Values set via the typed builder methods of
+// Event (like `Event.Str` or `Event.Int`) are
+// stored unboxed and can be accessed with the
+// accessor matching the Kind of the field
+// (like `AsString` or `AsInt64`). Value returns
+// the value of any kind as interface.
+//
+// Key holds the key as passed to the event. Use
+// KeyString to get it as string.
+type Field struct {
+	Key any
+	Val any
+
+	kind FieldKind
+	num  uint64
+	str  string
+	tm   time.Time
+	strs []string
+	ints []int
+	byts []byte
+}
+
+func (t *Field) Reset() {
+	t.Key = nil
+	t.Val = nil
+	t.kind = KindAny
+	t.num = 0
+	t.str = ""
+	t.tm = time.Time{}
+	t.strs = nil
+	t.ints = nil
+	t.byts = nil
+}
+
+// Kind returns the kind of the fields value.
+func (t *Field) Kind() FieldKind {
+	return t.kind
+}
+
+// Value returns the value of the field
+// as interface.
+func (t *Field) Value() any {
+	switch t.kind {
+	case KindString:
+		return t.str
+	case KindInt64:
+		return t.AsInt64()
+	case KindUint64:
+		return t.num
+	case KindFloat64:
+		return t.AsFloat64()
+	case KindBool:
+		return t.AsBool()
+	case KindDuration:
+		return t.AsDuration()
+	case KindTime:
+		return t.tm
+	case KindBytes:
+		return t.byts
+	case KindStrings:
+		return t.strs
+	case KindInts:
+		return t.ints
+	}
+	return t.Val
+}
+
+// AsString returns the value of a KindString field.
+func (t *Field) AsString() string { return t.str }
+
+// AsInt64 returns the value of a KindInt64 field.
+func (t *Field) AsInt64() int64 { return int64(t.num) }
+
+// AsUint64 returns the value of a KindUint64 field.
+func (t *Field) AsUint64() uint64 { return t.num }
+
+// AsFloat64 returns the value of a KindFloat64 field.
+func (t *Field) AsFloat64() float64 { return math.Float64frombits(t.num) }
+
+// AsBool returns the value of a KindBool field.
+func (t *Field) AsBool() bool { return t.num == 1 }
+
+// AsDuration returns the value of a KindDuration field.
+func (t *Field) AsDuration() time.Duration { return time.Duration(t.num) }
+
+// AsTime returns the value of a KindTime field.
+func (t *Field) AsTime() time.Time { return t.tm }
+
+// AsBytes returns the value of a KindBytes field.
+func (t *Field) AsBytes() []byte { return t.byts }
+
+// AsStrings returns the value of a KindStrings field.
+func (t *Field) AsStrings() []string { return t.strs }
+
+// AsInts returns the value of a KindInts field.
+func (t *Field) AsInts() []int { return t.ints }
+
+// KeyString returns the key of the field as
+// string.
+func (t *Field) KeyString() string {
+	return keyString(t.Key)
+}
+
+// keyString returns v as string to be used
+// as key of a Field.
+func keyString(v any) string {
+	switch vt := v.(type) {
+	case string:
+		return vt
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// maxBoxedKeys limits the number of keys cached
+// by boxKey.
+const maxBoxedKeys = 1024
+
+var (
+	boxedKeys   atomic.Pointer[map[string]any]
+	boxedKeysMu sync.Mutex
+)
+
+// boxKey returns key as interface value. Keys are
+// cached once boxed, so that the typed builder
+// methods of Event do not allocate for setting
+// the key of a field, which is usually one of a
+// few constant strings.
+func boxKey(key string) any {
+	if m := boxedKeys.Load(); m != nil {
+		if v, ok := (*m)[key]; ok {
+			return v
+		}
+	}
+
+	v := any(key)
+
+	boxedKeysMu.Lock()
+	defer boxedKeysMu.Unlock()
+
+	old := boxedKeys.Load()
+	if old != nil && len(*old) >= maxBoxedKeys {
+		return v
+	}
+
+	// The map is replaced instead of modified, so
+	// it can be read without locking.
+	m := make(map[string]any, 1)
+	if old != nil {
+		m = make(map[string]any, len(*old)+1)
+		for k, kv := range *old {
+			m[k] = kv
+		}
+	}
+	m[key] = v
+	boxedKeys.Store(&m)
+
+	return v
+}
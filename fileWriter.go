@@ -0,0 +1,412 @@
+package rogu
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions specifies when a RotatingFile is
+// rotated and how rotated files are kept.
+type RotateOptions struct {
+	// MaxSize is the maximum size of the file in
+	// bytes before it is rotated. When set to 0,
+	// the file is not rotated by size.
+	MaxSize int64
+	// Interval is the duration after which the
+	// file is rotated. When set to 0, the file is
+	// not rotated by time.
+	Interval time.Duration
+	// MaxBackups is the maximum number of rotated
+	// files which are kept. When set to 0, all
+	// rotated files are kept.
+	MaxBackups int
+	// Compress enables gzip compression of rotated
+	// files, which is performed in the background.
+	Compress bool
+}
+
+// RotatingFile implements io.WriteCloser and writes
+// to a file which is rotated according to the given
+// RotateOptions.
+//
+// Rotated files are renamed to the name of the file
+// with the time of rotation attached, for example
+// `app-2006-01-02T15-04-05.000.log`.
+//
+// A RotatingFile can be used as Output of a
+// JsonWriter or PrettyWriter.
+type RotatingFile struct {
+	RotateOptions
+
+	path string
+
+	mtx      sync.Mutex
+	f        *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	sigCh chan os.Signal
+	bgWg  sync.WaitGroup
+	// bgMtx serializes the compression and removal
+	// of backups, so that backups are not removed
+	// while they are compressed.
+	bgMtx sync.Mutex
+}
+
+var (
+	_ io.Writer = (*RotatingFile)(nil)
+	_ Closer    = (*RotatingFile)(nil)
+)
+
+// NewRotatingFile opens or creates the file at path
+// and returns a new RotatingFile writing to it. Missing
+// parent directories are created.
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	t := &RotatingFile{
+		RotateOptions: opts,
+		path:          path,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Write writes p to the file. If writing p would
+// exceed MaxSize or the Interval has passed, the
+// file is rotated before writing.
+//
+// When the rotation fails, p is still written to
+// the current file and the error of the rotation
+// is returned.
+func (t *RotatingFile) Write(p []byte) (n int, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.closed {
+		return 0, ErrWriterClosed
+	}
+
+	var rotateErr error
+	if t.f != nil && t.shouldRotate(len(p)) {
+		rotateErr = t.rotate()
+	}
+
+	// The file might not be open when reopening
+	// it failed before.
+	if t.f == nil {
+		if err = t.open(); err != nil {
+			return 0, errors.Join(rotateErr, err)
+		}
+	}
+
+	n, err = t.f.Write(p)
+	t.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Rotate rotates the file regardless of the
+// RotateOptions.
+func (t *RotatingFile) Rotate() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.closed {
+		return ErrWriterClosed
+	}
+
+	return t.rotate()
+}
+
+// Reopen closes and reopens the file. This is
+// useful when the file has been moved by an
+// external tool like logrotate.
+func (t *RotatingFile) Reopen() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.closed {
+		return ErrWriterClosed
+	}
+
+	err := t.closeFile()
+	if oErr := t.open(); oErr != nil {
+		return oErr
+	}
+	return err
+}
+
+// ReopenOnSignal reopens the file every time one
+// of the given signals is received. When no signal
+// is specified, SIGHUP is used on Unix systems. On
+// other systems, nothing happens in this case.
+//
+// Listening for signals is stopped when the file
+// is closed.
+func (t *RotatingFile) ReopenOnSignal(sig ...os.Signal) {
+	if len(sig) == 0 {
+		sig = defaultReopenSignals
+	}
+	if len(sig) == 0 {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.sigCh != nil || t.closed {
+		return
+	}
+
+	t.sigCh = make(chan os.Signal, 1)
+	signal.Notify(t.sigCh, sig...)
+
+	go func(ch chan os.Signal) {
+		for range ch {
+			t.Reopen()
+		}
+	}(t.sigCh)
+}
+
+// Close closes the file and waits for running
+// compressions of rotated files to finish.
+func (t *RotatingFile) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+
+	if t.sigCh != nil {
+		signal.Stop(t.sigCh)
+		close(t.sigCh)
+	}
+
+	err := t.closeFile()
+	t.mtx.Unlock()
+
+	t.bgWg.Wait()
+
+	return err
+}
+
+func (t *RotatingFile) open() error {
+	f, err := os.OpenFile(t.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	t.f = f
+	t.size = stat.Size()
+	t.openedAt = time.Now()
+
+	return nil
+}
+
+func (t *RotatingFile) shouldRotate(n int) bool {
+	if t.MaxSize > 0 && t.size > 0 && t.size+int64(n) > t.MaxSize {
+		return true
+	}
+	if t.Interval > 0 && time.Since(t.openedAt) >= t.Interval {
+		return true
+	}
+	return false
+}
+
+// closeFile closes the current file, if it is
+// open.
+func (t *RotatingFile) closeFile() error {
+	if t.f == nil {
+		return nil
+	}
+	err := t.f.Close()
+	t.f = nil
+	return err
+}
+
+// rotate renames the current file to a backup and
+// opens a new file. On failure, the current file
+// is reopened, so writing can continue.
+func (t *RotatingFile) rotate() error {
+	if err := t.closeFile(); err != nil {
+		return errors.Join(err, t.open())
+	}
+
+	now := time.Now()
+	backup := t.backupName(now)
+	for fileExists(backup) || fileExists(backup+".gz") {
+		now = now.Add(time.Millisecond)
+		backup = t.backupName(now)
+	}
+
+	if err := os.Rename(t.path, backup); err != nil {
+		return errors.Join(err, t.open())
+	}
+
+	if err := t.open(); err != nil {
+		return err
+	}
+
+	t.bgWg.Add(1)
+	go func() {
+		defer t.bgWg.Done()
+
+		t.bgMtx.Lock()
+		defer t.bgMtx.Unlock()
+
+		if t.Compress && fileExists(backup) {
+			compressFile(backup)
+		}
+		t.removeOldBackups()
+	}()
+
+	return nil
+}
+
+func (t *RotatingFile) backupName(now time.Time) string {
+	dir, name, ext := t.splitPath()
+	return filepath.Join(dir, name+"-"+now.Format(backupTimeFormat)+ext)
+}
+
+func (t *RotatingFile) splitPath() (dir, name, ext string) {
+	dir = filepath.Dir(t.path)
+	name = filepath.Base(t.path)
+	ext = filepath.Ext(name)
+	name = strings.TrimSuffix(name, ext)
+	return dir, name, ext
+}
+
+func (t *RotatingFile) removeOldBackups() {
+	if t.MaxBackups <= 0 {
+		return
+	}
+
+	dir, name, ext := t.splitPath()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	// A backup might exist compressed and
+	// uncompressed when compressing it failed, so
+	// backups are counted by their timestamp.
+	backups := make(map[string][]string)
+	for _, e := range entries {
+		ts := strings.TrimPrefix(e.Name(), name+"-")
+		if ts == e.Name() {
+			continue
+		}
+		ts = strings.TrimSuffix(ts, ".gz")
+		ts = strings.TrimSuffix(ts, ext)
+		if _, err := time.Parse(backupTimeFormat, ts); err != nil {
+			continue
+		}
+		backups[ts] = append(backups[ts], e.Name())
+	}
+
+	if len(backups) <= t.MaxBackups {
+		return
+	}
+
+	timestamps := make([]string, 0, len(backups))
+	for ts := range backups {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Strings(timestamps)
+
+	for _, ts := range timestamps[:len(timestamps)-t.MaxBackups] {
+		for _, b := range backups[ts] {
+			os.Remove(filepath.Join(dir, b))
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	if _, err = io.Copy(gw, src); err == nil {
+		err = gw.Close()
+	}
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// FileWriter implements Writer and writes JSON
+// formatted entries to a RotatingFile.
+//
+// The embedded JsonWriter can be configured
+// like any other JsonWriter.
+type FileWriter struct {
+	*JsonWriter
+
+	File *RotatingFile
+}
+
+var (
+	_ Writer = (*FileWriter)(nil)
+	_ Closer = (*FileWriter)(nil)
+)
+
+// NewFileWriter returns a new FileWriter writing
+// to a RotatingFile at the given path.
+func NewFileWriter(path string, opts RotateOptions) (*FileWriter, error) {
+	f, err := NewRotatingFile(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileWriter{
+		JsonWriter: NewJsonWriter(f),
+		File:       f,
+	}, nil
+}
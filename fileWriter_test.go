@@ -0,0 +1,222 @@
+package rogu
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func backups(t *testing.T, dir string) (plain, compressed int) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		switch {
+		case e.Name() == "app.log":
+		case strings.HasSuffix(e.Name(), ".log.gz"):
+			compressed++
+		case strings.HasSuffix(e.Name(), ".log"):
+			plain++
+		}
+	}
+	return plain, compressed
+}
+
+func TestRotatingFileMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewRotatingFile(filepath.Join(dir, "app.log"), RotateOptions{
+		MaxSize:    10,
+		MaxBackups: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err = f.Write([]byte("0123456789")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if plain, _ := backups(t, dir); plain != 2 {
+		t.Errorf("expected 2 backups, got %d", plain)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0123456789" {
+		t.Errorf("unexpected file content: %q", data)
+	}
+}
+
+func TestRotatingFileRotateError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewRotatingFile(path, RotateOptions{MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Renaming fails when the file has been removed.
+	if err = os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = f.Write([]byte("abc")); err == nil {
+		t.Error("expected rotation error")
+	}
+	if _, err = f.Write([]byte("def")); err != nil {
+		t.Fatalf("write after failed rotation: %s", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "abcdef" {
+		t.Errorf("unexpected file content: %q", data)
+	}
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewRotatingFile(filepath.Join(dir, "app.log"), RotateOptions{
+		Compress: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Write([]byte("foo"))
+	if err = f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("bar"))
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if plain, compressed := backups(t, dir); plain != 0 || compressed != 1 {
+		t.Errorf("expected 1 compressed backup, got %d plain and %d compressed",
+			plain, compressed)
+	}
+}
+
+func TestRotatingFileCompressMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewRotatingFile(filepath.Join(dir, "app.log"), RotateOptions{
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		f.Write([]byte(strings.Repeat("x", 100000)))
+		if err = f.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if plain, compressed := backups(t, dir); plain != 0 || compressed != 2 {
+		t.Errorf("expected 2 compressed backups, got %d plain and %d compressed",
+			plain, compressed)
+	}
+}
+
+func TestRotatingFileRemoveOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewRotatingFile(filepath.Join(dir, "app.log"), RotateOptions{MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, name := range []string{
+		"app-2024-01-01T00-00-00.000.log",
+		"app-2024-01-02T00-00-00.000.log",
+		"app-2024-01-02T00-00-00.000.log.gz",
+		"app-2024-01-03T00-00-00.000.log.gz",
+	} {
+		if err = os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f.removeOldBackups()
+
+	plain, compressed := backups(t, dir)
+	if plain != 1 || compressed != 2 || fileExists(filepath.Join(dir, "app-2024-01-01T00-00-00.000.log")) {
+		t.Errorf("unexpected backups: %d plain and %d compressed", plain, compressed)
+	}
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewRotatingFile(path, RotateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	f.Write([]byte("foo"))
+	if err = os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("bar"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "bar" {
+		t.Errorf("unexpected file content: %q", data)
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(filepath.Join(dir, "app.log"), RotateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogger(w)
+	l.Info().Msg("hello")
+
+	if err = l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"message":"hello"`) {
+		t.Errorf("unexpected file content: %q", data)
+	}
+}
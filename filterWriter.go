@@ -0,0 +1,113 @@
+package rogu
+
+import "github.com/zekrotja/rogu/level"
+
+// FilterFunc returns true if the given entry
+// should be passed to the wrapped writer.
+type FilterFunc func(e Entry) bool
+
+// LevelFilter passes entries which are at least
+// as severe as lvl, like the level set to a logger.
+func LevelFilter(lvl level.Level) FilterFunc {
+	return func(e Entry) bool {
+		return e.Level <= lvl
+	}
+}
+
+// LevelRangeFilter passes entries with a level
+// between the most severe and the least severe
+// level, both inclusive.
+//
+// Example:
+//
+//	// passes debug and info entries
+//	LevelRangeFilter(level.Info, level.Debug)
+func LevelRangeFilter(mostSevere, leastSevere level.Level) FilterFunc {
+	return func(e Entry) bool {
+		return e.Level >= mostSevere && e.Level <= leastSevere
+	}
+}
+
+// TagFilter passes entries which have one of
+// the given tags.
+func TagFilter(tags ...string) FilterFunc {
+	return func(e Entry) bool {
+		return containsTag(tags, e.Tag)
+	}
+}
+
+// ExcludeTagFilter passes entries which have
+// none of the given tags.
+func ExcludeTagFilter(tags ...string) FilterFunc {
+	return func(e Entry) bool {
+		return !containsTag(tags, e.Tag)
+	}
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterWriter implements Writer and passes only
+// entries to the wrapped writer which match all
+// of its Filters.
+//
+// Mind that the level of the logger is checked
+// before entries are passed to any writer. So to
+// pass debug entries to a FilterWriter, the level
+// of the logger must be set to level.Debug or
+// lower.
+type FilterWriter struct {
+	Filters []FilterFunc
+
+	w Writer
+}
+
+var (
+	_ Writer = (*FilterWriter)(nil)
+	_ Closer = (*FilterWriter)(nil)
+)
+
+// NewFilterWriter returns a new FilterWriter
+// passing entries matching all of the given
+// filters to w.
+func NewFilterWriter(w Writer, filters ...FilterFunc) *FilterWriter {
+	return &FilterWriter{w: w, Filters: filters}
+}
+
+// NewLevelWriter returns a new FilterWriter
+// passing entries to w which are at least as
+// severe as lvl.
+func NewLevelWriter(w Writer, lvl level.Level) *FilterWriter {
+	return NewFilterWriter(w, LevelFilter(lvl))
+}
+
+// Allows returns true if e matches all
+// filters of the writer.
+func (t *FilterWriter) Allows(e Entry) bool {
+	for _, f := range t.Filters {
+		if !f(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *FilterWriter) Write(e Entry) error {
+	if !t.Allows(e) {
+		return nil
+	}
+	return t.w.Write(e)
+}
+
+func (t *FilterWriter) Close() error {
+	if c, ok := t.w.(Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
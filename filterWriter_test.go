@@ -0,0 +1,53 @@
+package rogu
+
+import (
+	"testing"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestFilterWriter(t *testing.T) {
+	var w recordWriter
+	fw := NewFilterWriter(&w,
+		LevelRangeFilter(level.Warn, level.Debug),
+		ExcludeTagFilter("noisy"),
+		func(e Entry) bool { return e.Message != "skip" })
+
+	l := NewLogger(fw).SetLevel(level.All)
+	l.Error().Msg("too severe")
+	l.Trace().Msg("not severe enough")
+	l.Info().Tag("noisy").Msg("excluded tag")
+	l.Info().Msg("skip")
+	l.Warn().Msg("warn")
+	l.Debug().Tag("db").Msg("debug")
+
+	if len(w.records) != 2 ||
+		w.records[0]["msg"] != "warn" ||
+		w.records[1]["msg"] != "debug" {
+		t.Errorf("unexpected records: %v", w.records)
+	}
+}
+
+func TestMultiWriterRoute(t *testing.T) {
+	var all, errs, db recordWriter
+	mw := MultiWriter{}.
+		Route(&all, LevelFilter(level.Debug)).
+		Route(&errs, LevelFilter(level.Error)).
+		Route(&db, TagFilter("db"))
+
+	l := NewLogger(mw).SetLevel(level.All)
+	l.Trace().Msg("trace")
+	l.Debug().Tag("db").Msg("query")
+	l.Info().Msg("info")
+	l.Error().Tag("http").Msg("error")
+
+	if len(all.records) != 3 {
+		t.Errorf("expected 3 records, got %d", len(all.records))
+	}
+	if len(errs.records) != 1 || errs.records[0]["msg"] != "error" {
+		t.Errorf("unexpected error records: %v", errs.records)
+	}
+	if len(db.records) != 1 || db.records[0]["msg"] != "query" {
+		t.Errorf("unexpected db records: %v", db.records)
+	}
+}
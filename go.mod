@@ -1,11 +1,14 @@
 module github.com/zekrotja/rogu
 
-go 1.18
+go 1.21
 
 require (
 	github.com/charmbracelet/lipgloss v0.6.0
 	github.com/mattn/go-colorable v0.1.13
 	github.com/muesli/termenv v0.11.1-0.20220204035834-5ac8409525e0
+	go.opentelemetry.io/otel/trace v1.28.0
+	golang.org/x/sys v0.0.0-20220811171246-fbc7d0a398ab
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,5 +17,5 @@ require (
 	github.com/mattn/go-runewidth v0.0.13 // indirect
 	github.com/muesli/reflow v0.2.1-0.20210115123740-9e1d0d53df68 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
-	golang.org/x/sys v0.0.0-20220811171246-fbc7d0a398ab // indirect
+	go.opentelemetry.io/otel v1.28.0 // indirect
 )
@@ -0,0 +1,78 @@
+package rogu
+
+// Hook is applied to every commited event which
+// passes the level of the logger before the event
+// is passed to the writer.
+//
+// Run may modify the entry, for example by adding,
+// replacing or removing fields via `AddFields`,
+// `SetField` and `RemoveField`. When Run returns
+// false, the event is dropped and not passed to
+// subsequent hooks and the writer. Like writers,
+// hooks must not retain the entry after Run has
+// returned.
+type Hook interface {
+	Run(e *Entry) bool
+}
+
+// HookFunc implements Hook for a function.
+//
+// Example:
+//
+//	hostname, _ := os.Hostname()
+//	l.AddHook(rogu.HookFunc(func(e *rogu.Entry) bool {
+//	    e.AddFields("host", hostname)
+//	    return true
+//	}))
+type HookFunc func(e *Entry) bool
+
+func (t HookFunc) Run(e *Entry) bool {
+	return t(e)
+}
+
+// Field returns the first field of the entry
+// with the given key or nil if the entry has no
+// such field.
+func (t *Entry) Field(key string) *Field {
+	for _, f := range t.Fields {
+		if f.KeyString() == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// AddFields adds the passed values alternating
+// as keys and values to the fields of the entry
+// like `Event.Fields`.
+func (t *Entry) AddFields(kv ...any) {
+	t.Fields = appendKV(t.Fields, kv)
+}
+
+// SetField replaces the value of all fields of
+// the entry with the given key. When the entry
+// has no such field, it is added.
+func (t *Entry) SetField(key string, val any) {
+	var found bool
+	for i, f := range t.Fields {
+		if f.KeyString() == key {
+			t.Fields[i] = &Field{Key: key, Val: val}
+			found = true
+		}
+	}
+	if !found {
+		t.Fields = append(t.Fields, &Field{Key: key, Val: val})
+	}
+}
+
+// RemoveField removes all fields of the entry
+// with the given key.
+func (t *Entry) RemoveField(key string) {
+	fields := t.Fields[:0]
+	for _, f := range t.Fields {
+		if f.KeyString() != key {
+			fields = append(fields, f)
+		}
+	}
+	t.Fields = fields
+}
@@ -0,0 +1,101 @@
+package rogu
+
+import (
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestLoggerHooks(t *testing.T) {
+	var (
+		w     entryWriter
+		order []int
+	)
+
+	l := NewLogger(&w)
+	tagged := l.Tagged("tag")
+
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		order = append(order, 1)
+		e.AddFields("host", "example")
+		e.SetField("password", "***")
+		e.RemoveField("internal")
+		return true
+	}))
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		order = append(order, 2)
+		return e.Level != level.Debug
+	}))
+	l.SetLevel(level.Debug)
+
+	tagged.Info().
+		Str("password", "secret").
+		Int("internal", 1).
+		Str("user", "bob").
+		Msg("login")
+	tagged.Debug().Msg("vetoed")
+
+	if len(w.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(w.entries))
+	}
+
+	e := w.entries[0]
+	if e.Tag != "tag" {
+		t.Errorf("unexpected tag: %s", e.Tag)
+	}
+
+	got := map[string]any{}
+	for _, f := range e.Fields {
+		got[f.KeyString()] = f.Value()
+	}
+	if len(got) != 3 || got["password"] != "***" || got["user"] != "bob" || got["host"] != "example" {
+		t.Errorf("unexpected fields: %v", got)
+	}
+
+	if want := []int{1, 2, 1, 2}; !slices.Equal(order, want) {
+		t.Errorf("hooks ran in order %v; want %v", order, want)
+	}
+}
+
+func TestLoggerHookVetoFatal(t *testing.T) {
+	var (
+		w      entryWriter
+		exited bool
+	)
+
+	l := NewLogger(&w).
+		SetExitFunc(func(int) { exited = true }).
+		AddHook(HookFunc(func(*Entry) bool { return false }))
+
+	l.Fatal().Msg("fatal")
+
+	if len(w.entries) != 0 {
+		t.Errorf("vetoed event was written: %+v", w.entries)
+	}
+	if !exited {
+		t.Error("vetoed fatal event did not exit")
+	}
+}
+
+func TestLoggerZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	for _, w := range []Writer{nopWriter{}, NewJsonWriter(io.Discard)} {
+		l := NewLogger(w)
+		allocs := testing.AllocsPerRun(100, func() {
+			l.Info().
+				Str("str", "str").
+				Int("int", 1000).
+				Dur("dur", time.Second).
+				Msg("allocs")
+		})
+		if allocs != 0 {
+			t.Errorf("%T: %v allocations per event; want 0", w, allocs)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package rogu
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPStatusError is returned when the endpoint of
+// an HTTPWriter responds with a non-2xx status code.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (t *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %s", t.StatusCode, t.Body)
+}
+
+func (t *HTTPStatusError) retryable() bool {
+	return t.StatusCode == http.StatusTooManyRequests || t.StatusCode >= 500
+}
+
+// HTTPWriter implements Writer and sends batches
+// of entries to an HTTP endpoint. The request body
+// is encoded by the Encoder.
+//
+// A batch is sent when it contains MaxBatchSize
+// entries or when its oldest entry is older than
+// MaxBatchAge.
+//
+// Batches which could not be sent due to network
+// errors or 429 and 5xx responses are retried with
+// exponential backoff up to MaxRetries times. While
+// a batch is being retried, up to MaxPending batches
+// are queued. When the queue is full, the oldest
+// batch is dropped.
+//
+// Errors are passed to OnError, if set.
+//
+// Close must be called to send all remaining
+// entries and to stop the background goroutine.
+type HTTPWriter struct {
+	// URL is the endpoint the batches are POSTed to.
+	URL string
+	// Encoder encodes the batches into the request
+	// body.
+	Encoder BatchEncoder
+	// Client is used to send the requests. The
+	// default client times out after 30 seconds.
+	Client *http.Client
+	// Header is added to each request.
+	Header http.Header
+	// Gzip enables gzip compression of the
+	// request body.
+	Gzip bool
+
+	// MaxBatchSize is the maximum number of entries
+	// per batch.
+	MaxBatchSize int
+	// MaxBatchAge is the maximum duration entries
+	// are held back before they are sent.
+	MaxBatchAge time.Duration
+	// MaxPending is the maximum number of batches
+	// queued for sending.
+	MaxPending int
+
+	// MaxRetries is the maximum number of retries
+	// for a failed batch.
+	MaxRetries int
+	// RetryBackoff is the delay before the first
+	// retry. It is doubled for each further retry
+	// up to MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+
+	// CloseTimeout is the maximum duration Close
+	// waits for the remaining batches to be sent.
+	// Batches waiting for a retry when it exceeds
+	// are dropped.
+	CloseTimeout time.Duration
+	// OnError is called with errors which occurred
+	// when sending batches.
+	OnError func(err error)
+
+	mtx        sync.Mutex
+	batch      []Entry
+	batchStart time.Time
+	pending    [][]Entry
+	closed     bool
+
+	startOnce sync.Once
+	wake      chan struct{}
+	quit      chan struct{}
+	// ctx is cancelled when Close times out to
+	// abort pending requests and retries.
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	dropped atomic.Uint64
+}
+
+var (
+	_ Writer = (*HTTPWriter)(nil)
+	_ Closer = (*HTTPWriter)(nil)
+)
+
+// NewHTTPWriter returns a new HTTPWriter sending
+// batches to url encoded by the given encoder.
+func NewHTTPWriter(url string, encoder BatchEncoder) *HTTPWriter {
+	var t HTTPWriter
+
+	t.URL = url
+	t.Encoder = encoder
+	t.Client = &http.Client{Timeout: 30 * time.Second}
+	t.Header = http.Header{}
+	t.MaxBatchSize = 100
+	t.MaxBatchAge = time.Second
+	t.MaxPending = 10
+	t.MaxRetries = 5
+	t.RetryBackoff = 500 * time.Millisecond
+	t.MaxRetryBackoff = 30 * time.Second
+	t.CloseTimeout = defaultCloseTimeout
+
+	t.wake = make(chan struct{}, 1)
+	t.quit = make(chan struct{})
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.done = make(chan struct{})
+
+	return &t
+}
+
+// Write adds a copy of the entry to the
+// current batch.
+//
+// If the writer has been closed, ErrWriterClosed
+// is returned.
+func (t *HTTPWriter) Write(e Entry) error {
+	t.startOnce.Do(func() {
+		go t.run()
+	})
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.closed {
+		return ErrWriterClosed
+	}
+
+	if len(t.batch) == 0 {
+		t.batchStart = time.Now()
+	}
+	t.batch = append(t.batch, e.Clone())
+
+	if len(t.batch) >= t.MaxBatchSize {
+		t.enqueue()
+	}
+
+	return nil
+}
+
+// Dropped returns the number of entries which
+// have been dropped because they could not be
+// sent or the queue was full.
+func (t *HTTPWriter) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+// Close stops accepting new entries, waits up to
+// CloseTimeout for the remaining entries to be
+// sent and stops the background goroutine.
+func (t *HTTPWriter) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.enqueue()
+	t.mtx.Unlock()
+
+	started := true
+	t.startOnce.Do(func() {
+		started = false
+	})
+	if !started {
+		return nil
+	}
+
+	close(t.quit)
+
+	timer := time.NewTimer(t.CloseTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-t.done:
+		return nil
+	case <-timer.C:
+		// Aborts the current request and stops
+		// waiting for retries, so that the background
+		// goroutine does not outlive Close for long.
+		t.cancel()
+		return ErrFlushTimeout
+	}
+}
+
+// enqueue moves the current batch to the pending
+// batches. t.mtx must be held.
+func (t *HTTPWriter) enqueue() {
+	if len(t.batch) == 0 {
+		return
+	}
+
+	t.pending = append(t.pending, t.batch)
+	t.batch = nil
+
+	if maxPending := t.MaxPending; maxPending > 0 && len(t.pending) > maxPending {
+		t.dropped.Add(uint64(len(t.pending[0])))
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+	}
+
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (t *HTTPWriter) next() ([]Entry, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if len(t.pending) == 0 {
+		return nil, false
+	}
+
+	batch := t.pending[0]
+	t.pending[0] = nil
+	t.pending = t.pending[1:]
+	return batch, true
+}
+
+func (t *HTTPWriter) run() {
+	defer close(t.done)
+
+	interval := t.MaxBatchAge / 2
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.wake:
+		case <-ticker.C:
+			t.mtx.Lock()
+			if len(t.batch) > 0 && time.Since(t.batchStart) >= t.MaxBatchAge {
+				t.enqueue()
+			}
+			t.mtx.Unlock()
+		case <-t.quit:
+			for batch, ok := t.next(); ok; batch, ok = t.next() {
+				t.sendWithRetry(batch)
+			}
+			return
+		}
+
+		for batch, ok := t.next(); ok; batch, ok = t.next() {
+			t.sendWithRetry(batch)
+		}
+	}
+}
+
+func (t *HTTPWriter) sendWithRetry(batch []Entry) {
+	body, err := t.encode(batch)
+	if err != nil {
+		t.fail(batch, err)
+		return
+	}
+
+	backoff := t.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = t.send(body)
+		if err == nil {
+			return
+		}
+
+		if se, ok := err.(*HTTPStatusError); ok && !se.retryable() ||
+			attempt >= t.MaxRetries || t.ctx.Err() != nil {
+			t.fail(batch, err)
+			return
+		}
+
+		if t.OnError != nil {
+			t.OnError(err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-t.ctx.Done():
+			timer.Stop()
+			t.dropped.Add(uint64(len(batch)))
+			return
+		}
+
+		if backoff *= 2; t.MaxRetryBackoff > 0 && backoff > t.MaxRetryBackoff {
+			backoff = t.MaxRetryBackoff
+		}
+	}
+}
+
+func (t *HTTPWriter) fail(batch []Entry, err error) {
+	t.dropped.Add(uint64(len(batch)))
+	if t.OnError != nil {
+		t.OnError(err)
+	}
+}
+
+func (t *HTTPWriter) encode(batch []Entry) ([]byte, error) {
+	b, err := t.Encoder.Encode(nil, batch)
+	if err != nil || !t.Gzip {
+		return b, err
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err = gw.Write(b); err != nil {
+		return nil, err
+	}
+	if err = gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (t *HTTPWriter) send(body []byte) error {
+	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range t.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", t.Encoder.ContentType())
+	if t.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	res, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return &HTTPStatusError{StatusCode: res.StatusCode, Body: string(msg)}
+}
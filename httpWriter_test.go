@@ -0,0 +1,307 @@
+package rogu
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type recordedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newRecordingServer returns a server recording all
+// requests. The status codes are returned in the
+// given order, 200 is returned afterwards.
+func newRecordingServer(t *testing.T, statusCodes ...int) (*httptest.Server, func() []recordedRequest) {
+	var (
+		mtx      sync.Mutex
+		requests []recordedRequest
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body = gr
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mtx.Lock()
+		requests = append(requests, recordedRequest{header: r.Header, body: data})
+		status := http.StatusOK
+		if len(requests) <= len(statusCodes) {
+			status = statusCodes[len(requests)-1]
+		}
+		mtx.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []recordedRequest {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]recordedRequest(nil), requests...)
+	}
+}
+
+func countLines(data []byte) (n int) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		n++
+	}
+	return n
+}
+
+func TestHTTPWriterBatching(t *testing.T) {
+	srv, requests := newRecordingServer(t)
+
+	w := NewHTTPWriter(srv.URL, NewNDJSONEncoder())
+	w.MaxBatchSize = 2
+	w.MaxBatchAge = time.Hour
+
+	l := NewLogger(w)
+	for i := 0; i < 5; i++ {
+		l.Info().Int("i", i).Msg("hello")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(reqs))
+	}
+
+	lines := 0
+	for _, r := range reqs {
+		if ct := r.header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("unexpected content type: %s", ct)
+		}
+		lines += countLines(r.body)
+	}
+	if lines != 5 {
+		t.Errorf("expected 5 entries, got %d", lines)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.SplitN(reqs[0].body, []byte("\n"), 2)[0], &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["msg"] != "hello" || entry["i"] != 0.0 {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+
+	if err := w.Write(Entry{}); err != ErrWriterClosed {
+		t.Errorf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+func TestHTTPWriterBatchAge(t *testing.T) {
+	srv, requests := newRecordingServer(t)
+
+	w := NewHTTPWriter(srv.URL, NewNDJSONEncoder())
+	w.MaxBatchAge = 10 * time.Millisecond
+	defer w.Close()
+
+	NewLogger(w).Info().Msg("hello")
+
+	deadline := time.Now().Add(time.Second)
+	for len(requests()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("batch has not been sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHTTPWriterRetry(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	var errs []error
+	w := NewHTTPWriter(srv.URL, NewNDJSONEncoder())
+	w.Gzip = true
+	w.RetryBackoff = time.Millisecond
+	w.OnError = func(err error) { errs = append(errs, err) }
+
+	NewLogger(w).Info().Msg("hello")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(reqs))
+	}
+	for _, r := range reqs {
+		if r.header.Get("Content-Encoding") != "gzip" || countLines(r.body) != 1 {
+			t.Errorf("unexpected request: %v %q", r.header, r.body)
+		}
+	}
+
+	if len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %d", len(errs))
+	}
+	if w.Dropped() != 0 {
+		t.Errorf("expected no dropped entries, got %d", w.Dropped())
+	}
+}
+
+func TestHTTPWriterCloseDuringBackoff(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusServiceUnavailable)
+
+	w := NewHTTPWriter(srv.URL, NewNDJSONEncoder())
+	w.RetryBackoff = time.Hour
+	w.CloseTimeout = 50 * time.Millisecond
+
+	NewLogger(w).Info().Msg("hello")
+	if err := w.Close(); err != ErrFlushTimeout {
+		t.Fatalf("expected ErrFlushTimeout, got %v", err)
+	}
+
+	select {
+	case <-w.done:
+	case <-time.After(time.Second):
+		t.Fatal("sending goroutine is still waiting for the retry")
+	}
+
+	if n := len(requests()); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", w.Dropped())
+	}
+}
+
+func TestHTTPWriterCloseHangingRequest(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	w := NewHTTPWriter(srv.URL, NewNDJSONEncoder())
+	w.CloseTimeout = 50 * time.Millisecond
+
+	NewLogger(w).Info().Msg("hello")
+	if err := w.Close(); err != ErrFlushTimeout {
+		t.Fatalf("expected ErrFlushTimeout, got %v", err)
+	}
+
+	select {
+	case <-w.done:
+	case <-time.After(time.Second):
+		t.Fatal("sending goroutine is still waiting for the request")
+	}
+
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", w.Dropped())
+	}
+}
+
+func TestHTTPWriterNonRetryable(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusBadRequest)
+
+	var errs []error
+	w := NewHTTPWriter(srv.URL, NewNDJSONEncoder())
+	w.RetryBackoff = time.Millisecond
+	w.OnError = func(err error) { errs = append(errs, err) }
+
+	NewLogger(w).Info().Msg("hello")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(requests()); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+
+	var se *HTTPStatusError
+	if len(errs) != 1 || !errors.As(errs[0], &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", w.Dropped())
+	}
+}
+
+func TestLokiEncoder(t *testing.T) {
+	ts := time.Unix(1700000000, 123)
+	entries := []Entry{
+		{Time: ts, Level: level.Info, Tag: "db", Message: "a"},
+		{Time: ts, Level: level.Error, Tag: "db", Message: "b"},
+		{Time: ts, Level: level.Info, Tag: "db", Message: "c"},
+	}
+
+	b, err := NewLokiEncoder(map[string]string{"app": "test"}).Encode(nil, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err = json.Unmarshal(b, &push); err != nil {
+		t.Fatalf("%s: %s", err, b)
+	}
+
+	if len(push.Streams) != 2 {
+		t.Fatalf("expected 2 streams, got %d", len(push.Streams))
+	}
+
+	s := push.Streams[0]
+	if s.Stream["app"] != "test" || s.Stream["level"] != "info" || s.Stream["tag"] != "db" {
+		t.Errorf("unexpected labels: %v", s.Stream)
+	}
+	if len(s.Values) != 2 || s.Values[0][0] != "1700000000000000123" || s.Values[1][1] != "level=info tag=db msg=c" {
+		t.Errorf("unexpected values: %v", s.Values)
+	}
+}
+
+func TestElasticsearchEncoder(t *testing.T) {
+	entries := []Entry{
+		{Level: level.Info, Message: "a"},
+		{Level: level.Warn, Message: "b"},
+	}
+
+	b, err := NewElasticsearchEncoder("logs").Encode(nil, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"create":{"_index":"logs"}}
+{"log.level":"info","message":"a"}
+{"create":{"_index":"logs"}}
+{"log.level":"warn","message":"b"}
+`
+	if string(b) != expected {
+		t.Errorf("unexpected body:\n%s", b)
+	}
+}
@@ -0,0 +1,236 @@
+package rogu
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// DefaultJournaldSocket is the path of the native
+// protocol socket of systemd-journald.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// ErrJournaldUnsupported is returned when an entry
+// is too large to be sent as single datagram and
+// the platform does not support passing it to
+// journald via file descriptor.
+var ErrJournaldUnsupported = errors.New("sending large entries to journald is not supported on this platform")
+
+// JournaldWriter implements Writer and sends
+// entries to systemd-journald using the native
+// journal protocol.
+//
+// The level is mapped to PRIORITY, the tag to
+// SYSLOG_IDENTIFIER, the caller to CODE_FILE and
+// CODE_LINE, the error to ERROR and additional
+// errors to ERROR_<NAME>. Field keys are converted
+// to uppercase journal field names like `user_id`
+// to `USER_ID`. Fields whose name would collide
+// with those or other fields known to journald,
+// like `message` or `error_db`, are prefixed with
+// `F_` like `F_MESSAGE`.
+//
+// Entries which are too large to be sent as a
+// single datagram are written to a sealed memfd
+// (or an unlinked temporary file as fallback) which
+// is then passed to journald.
+type JournaldWriter struct {
+	mtx  sync.Mutex
+	conn *net.UnixConn
+
+	// Socket is the path of the journald socket.
+	Socket string
+	// Identifier is used as SYSLOG_IDENTIFIER for
+	// entries without tag.
+	Identifier string
+}
+
+var (
+	_ Writer = (*JournaldWriter)(nil)
+	_ Closer = (*JournaldWriter)(nil)
+)
+
+// NewJournaldWriter returns a new JournaldWriter
+// sending entries to DefaultJournaldSocket.
+func NewJournaldWriter() *JournaldWriter {
+	var t JournaldWriter
+
+	t.Socket = DefaultJournaldSocket
+	t.Identifier = filepath.Base(os.Args[0])
+
+	return &t
+}
+
+func (t *JournaldWriter) Write(e Entry) (err error) {
+	buf := bufferPool.Get()
+	defer func() {
+		if buf.Cap() == bufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	b := buf.AvailableBuffer()
+
+	b = appendJournaldField(b, "MESSAGE", e.Message)
+	b = appendJournaldField(b, "PRIORITY", strconv.Itoa(syslogSeverity(e.Level)))
+
+	identifier := e.Tag
+	if identifier == "" {
+		identifier = t.Identifier
+	}
+	if identifier != "" {
+		b = appendJournaldField(b, "SYSLOG_IDENTIFIER", identifier)
+	}
+
+	if e.Err != nil {
+		b = appendJournaldField(b, "ERROR", e.ErrString())
+	}
+
+	for _, ne := range e.Errs {
+		b = appendJournaldField(b, journaldFieldName("error."+ne.Name), ne.String())
+	}
+
+	if e.CallerFile != "" {
+		b = appendJournaldField(b, "CODE_FILE", e.CallerFile)
+		b = appendJournaldField(b, "CODE_LINE", strconv.Itoa(e.CallerLine))
+	}
+
+	for _, f := range e.Fields {
+		b = appendJournaldField(b, journaldUserFieldName(f.KeyString()), syslogFieldValue(f))
+	}
+
+	buf.Write(b)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if err = t.send(buf.Bytes()); err == nil {
+		return nil
+	}
+
+	// Retry once with a new connection.
+	t.closeConn()
+	return t.send(buf.Bytes())
+}
+
+// Close closes the connection to journald.
+func (t *JournaldWriter) Close() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.closeConn()
+}
+
+func (t *JournaldWriter) send(msg []byte) (err error) {
+	if t.conn == nil {
+		addr := &net.UnixAddr{Name: t.Socket, Net: "unixgram"}
+		if t.conn, err = net.DialUnix("unixgram", nil, addr); err != nil {
+			return err
+		}
+	}
+
+	_, err = t.conn.Write(msg)
+	if isMessageTooLarge(err) {
+		return sendJournaldFd(t.conn, msg)
+	}
+	return err
+}
+
+func (t *JournaldWriter) closeConn() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+func isMessageTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// appendJournaldField appends the given field in
+// the native journal protocol format to b. Values
+// containing newlines are serialized as binary
+// data prefixed with their length.
+func appendJournaldField(b []byte, name, value string) []byte {
+	b = append(b, name...)
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\n' {
+			b = append(b, '\n')
+			b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+			b = append(b, value...)
+			return append(b, '\n')
+		}
+	}
+
+	b = append(b, '=')
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// journaldReservedFields are the names of the
+// fields written by the JournaldWriter itself and
+// of other fields with special meaning to journald.
+var journaldReservedFields = []string{
+	"MESSAGE", "MESSAGE_ID", "PRIORITY", "CODE_FILE", "CODE_LINE", "CODE_FUNC",
+	"ERRNO", "INVOCATION_ID", "USER_INVOCATION_ID", "SYSLOG_FACILITY",
+	"SYSLOG_IDENTIFIER", "SYSLOG_PID", "SYSLOG_TIMESTAMP", "SYSLOG_RAW",
+	"DOCUMENTATION", "TID", "UNIT", "USER_UNIT", "ERROR",
+}
+
+// journaldUserFieldName converts the key of a field
+// to a journal field name like journaldFieldName.
+// Names which are reserved or collide with the names
+// of additional errors are prefixed with `F_`.
+func journaldUserFieldName(key string) string {
+	name := journaldFieldName(key)
+	if !slices.Contains(journaldReservedFields, name) && !strings.HasPrefix(name, "ERROR_") {
+		return name
+	}
+	name = "F_" + name
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// journaldFieldName converts the given key to a
+// valid journal field name. Names may only consist
+// of uppercase letters, digits and underscores, must
+// not start with an underscore or digit and must not
+// be longer than 64 characters.
+func journaldFieldName(key string) string {
+	name := make([]byte, 0, len(key)+2)
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			c = '_'
+		}
+		if len(name) == 0 && c == '_' {
+			continue
+		}
+		name = append(name, c)
+	}
+
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		name = append([]byte("F_"), name...)
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return string(name)
+}
@@ -0,0 +1,80 @@
+package rogu
+
+import (
+	"net"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// sendJournaldFd writes msg to a sealed memfd and
+// passes its file descriptor to journald. When
+// memfds are not available, an unlinked temporary
+// file in /dev/shm is used instead.
+func sendJournaldFd(conn *net.UnixConn, msg []byte) error {
+	f, err := journaldMemfd(msg)
+	if err != nil {
+		if f, err = journaldTempFile(msg); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+
+	// WriteMsgUnix refuses to write to connected
+	// datagram sockets, so sendmsg is called directly.
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	rights := syscall.UnixRights(int(f.Fd()))
+	werr := rc.Write(func(fd uintptr) bool {
+		err = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return err != syscall.EAGAIN
+	})
+	if werr != nil {
+		return werr
+	}
+	return err
+}
+
+func journaldMemfd(msg []byte) (*os.File, error) {
+	fd, err := unix.MemfdCreate("rogu-journal", unix.MFD_ALLOW_SEALING|unix.MFD_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+
+	f := os.NewFile(uintptr(fd), "rogu-journal")
+	if _, err = f.Write(msg); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+	if _, err = unix.FcntlInt(f.Fd(), unix.F_ADD_SEALS, seals); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func journaldTempFile(msg []byte) (*os.File, error) {
+	f, err := os.CreateTemp("/dev/shm", "rogu-journal-*")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Remove(f.Name()); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if _, err = f.Write(msg); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
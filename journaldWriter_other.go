@@ -0,0 +1,9 @@
+//go:build !linux
+
+package rogu
+
+import "net"
+
+func sendJournaldFd(conn *net.UnixConn, msg []byte) error {
+	return ErrJournaldUnsupported
+}
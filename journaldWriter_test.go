@@ -0,0 +1,251 @@
+//go:build linux
+
+package rogu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeJournal listens on a unixgram socket and
+// parses received entries in the native journal
+// protocol format. Entries passed via file
+// descriptor are read from the file.
+type fakeJournal struct {
+	conn *net.UnixConn
+	path string
+}
+
+func newFakeJournal(t *testing.T) *fakeJournal {
+	dir, err := os.MkdirTemp("", "rogu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &fakeJournal{conn: conn, path: path}
+}
+
+func (t *fakeJournal) read() (map[string]string, error) {
+	buf := make([]byte, 64*1024)
+	oob := make([]byte, 1024)
+
+	t.conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, oobn, _, _, err := t.conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, err
+	}
+
+	data := buf[:n]
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			return nil, err
+		}
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		if err != nil {
+			return nil, err
+		}
+		f := os.NewFile(uintptr(fds[0]), "journal")
+		defer f.Close()
+		// journald reads the file from the start
+		// regardless of the current offset.
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return parseJournalEntry(data)
+}
+
+func parseJournalEntry(data []byte) (map[string]string, error) {
+	fields := map[string]string{}
+	for len(data) > 0 {
+		i := bytes.IndexAny(data, "=\n")
+		if i < 0 {
+			return nil, errors.New("invalid entry")
+		}
+		name := string(data[:i])
+		if data[i] == '=' {
+			end := bytes.IndexByte(data, '\n')
+			fields[name] = string(data[i+1 : end])
+			data = data[end+1:]
+			continue
+		}
+		data = data[i+1:]
+		size := binary.LittleEndian.Uint64(data)
+		data = data[8:]
+		fields[name] = string(data[:size])
+		data = data[size+1:]
+	}
+	return fields, nil
+}
+
+func TestJournaldWriter(t *testing.T) {
+	j := newFakeJournal(t)
+
+	w := NewJournaldWriter()
+	w.Socket = j.path
+	defer w.Close()
+
+	err := NewLogger(w).
+		Warn().
+		Caller().
+		Tag("Database").
+		Err(errors.New("line 1\nline 2")).
+		Str("user_id", "123").
+		Int("retry.count", 2).
+		Str("_private", "x").
+		Str("2fa", "on").
+		Msg("query failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := j.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"MESSAGE":           "query failed",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "Database",
+		"ERROR":             "line 1\nline 2",
+		"USER_ID":           "123",
+		"RETRY_COUNT":       "2",
+		"PRIVATE":           "x",
+		"F_2FA":             "on",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, fields[k])
+		}
+	}
+
+	if !strings.HasSuffix(fields["CODE_FILE"], "journaldWriter_test.go") {
+		t.Errorf("unexpected CODE_FILE: %q", fields["CODE_FILE"])
+	}
+	if fields["CODE_LINE"] == "" {
+		t.Error("CODE_LINE is missing")
+	}
+}
+
+func TestJournaldWriterErrs(t *testing.T) {
+	j := newFakeJournal(t)
+
+	w := NewJournaldWriter()
+	w.Socket = j.path
+	defer w.Close()
+
+	err := NewLogger(w).
+		Error().
+		Errs(errors.New("a"), errors.New("b")).
+		NamedErr("db", errors.New("db down")).
+		Msg("failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := j.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fields["ERROR"] != "a" || fields["ERROR_1"] != "b" || fields["ERROR_DB"] != "db down" {
+		t.Errorf("unexpected errors: %v", fields)
+	}
+}
+
+func TestJournaldWriterReservedFields(t *testing.T) {
+	j := newFakeJournal(t)
+
+	w := NewJournaldWriter()
+	w.Socket = j.path
+	defer w.Close()
+
+	err := NewLogger(w).
+		Error().
+		Tag("Database").
+		NamedErr("db", errors.New("db down")).
+		Str("message", "user").
+		Str("priority", "0").
+		Str("syslog_identifier", "other").
+		Str("code_line", "1").
+		Str("error.db", "user").
+		Str("error", "user").
+		Msg("failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := j.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"MESSAGE":             "failed",
+		"PRIORITY":            "3",
+		"SYSLOG_IDENTIFIER":   "Database",
+		"ERROR_DB":            "db down",
+		"F_MESSAGE":           "user",
+		"F_PRIORITY":          "0",
+		"F_SYSLOG_IDENTIFIER": "other",
+		"F_CODE_LINE":         "1",
+		"F_ERROR_DB":          "user",
+		"F_ERROR":             "user",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, fields[k])
+		}
+	}
+	if _, ok := fields["CODE_LINE"]; ok {
+		t.Error("unexpected CODE_LINE without caller")
+	}
+}
+
+func TestJournaldWriterLargeEntry(t *testing.T) {
+	j := newFakeJournal(t)
+
+	w := NewJournaldWriter()
+	w.Socket = j.path
+	defer w.Close()
+
+	large := strings.Repeat("a", 4*1024*1024)
+	if err := NewLogger(w).Info().Str("payload", large).Msg("large"); err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := j.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fields["MESSAGE"] != "large" {
+		t.Errorf("unexpected MESSAGE: %q", fields["MESSAGE"])
+	}
+	if fields["PAYLOAD"] != large {
+		t.Errorf("unexpected PAYLOAD length: %d", len(fields["PAYLOAD"]))
+	}
+}
@@ -0,0 +1,221 @@
+package rogu
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// appendJsonString appends s as quoted and
+// escaped JSON string to b.
+func appendJsonString[S string | []byte](b []byte, s S) []byte {
+	b = append(b, '"')
+
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := decodeRune(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+
+		// U+2028 and U+2029 are valid JSON but break
+		// JavaScript parsers, so they are escaped like
+		// encoding/json does.
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+
+		i += size
+	}
+
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+func decodeRune[S string | []byte](s S) (rune, int) {
+	switch v := any(s).(type) {
+	case string:
+		return utf8.DecodeRuneInString(v)
+	case []byte:
+		return utf8.DecodeRune(v)
+	}
+	return utf8.RuneError, 1
+}
+
+// appendJsonKey appends key as quoted JSON string
+// followed by a colon to b.
+func appendJsonKey(b []byte, key string) []byte {
+	b = appendJsonString(b, key)
+	return append(b, ':')
+}
+
+// appendJsonNextKey appends a comma, if the key is
+// not the first one of the current object, and the
+// key followed by a colon to b.
+func appendJsonNextKey(b []byte, key string) []byte {
+	if len(b) > 0 && b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	return appendJsonKey(b, key)
+}
+
+func appendJsonFloat(b []byte, v float64, bitSize int) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return appendJsonString(b, strconv.FormatFloat(v, 'g', -1, bitSize))
+	}
+	return strconv.AppendFloat(b, v, 'g', -1, bitSize)
+}
+
+func appendJsonTime(b []byte, v time.Time, format string) []byte {
+	var tb [64]byte
+	return appendJsonString(b, v.AppendFormat(tb[:0], format))
+}
+
+// appendJsonField appends the value of f as
+// JSON to b. Fields of kind KindAny and KindObject
+// are encoded via appendJsonAny.
+func appendJsonField(b []byte, f *Field) ([]byte, error) {
+	switch f.kind {
+	case KindString:
+		return appendJsonString(b, f.str), nil
+	case KindInt64:
+		return strconv.AppendInt(b, f.AsInt64(), 10), nil
+	case KindUint64:
+		return strconv.AppendUint(b, f.num, 10), nil
+	case KindFloat64:
+		return appendJsonFloat(b, f.AsFloat64(), 64), nil
+	case KindBool:
+		return strconv.AppendBool(b, f.AsBool()), nil
+	case KindDuration:
+		return strconv.AppendInt(b, f.AsInt64(), 10), nil
+	case KindTime:
+		return appendJsonTime(b, f.tm, time.RFC3339Nano), nil
+	case KindBytes:
+		return appendJsonString(b, f.byts), nil
+	case KindStrings:
+		b = append(b, '[')
+		for i, v := range f.strs {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJsonString(b, v)
+		}
+		return append(b, ']'), nil
+	case KindInts:
+		b = append(b, '[')
+		for i, v := range f.ints {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = strconv.AppendInt(b, int64(v), 10)
+		}
+		return append(b, ']'), nil
+	case KindStringer:
+		// String of a nil pointer would panic
+		// for most implementations.
+		if rv := reflect.ValueOf(f.Val); f.Val == nil || rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return appendJsonString(b, f.Val.(interface{ String() string }).String()), nil
+	}
+
+	return appendJsonAny(b, f.Val)
+}
+
+// appendJsonAny appends v as JSON to b. Common
+// primitive types are encoded directly, all other
+// values are encoded using encoding/json.
+func appendJsonAny(b []byte, v any) ([]byte, error) {
+	switch vt := v.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case string:
+		return appendJsonString(b, vt), nil
+	case int:
+		return strconv.AppendInt(b, int64(vt), 10), nil
+	case int8:
+		return strconv.AppendInt(b, int64(vt), 10), nil
+	case int16:
+		return strconv.AppendInt(b, int64(vt), 10), nil
+	case int32:
+		return strconv.AppendInt(b, int64(vt), 10), nil
+	case int64:
+		return strconv.AppendInt(b, vt, 10), nil
+	case uint:
+		return strconv.AppendUint(b, uint64(vt), 10), nil
+	case uint8:
+		return strconv.AppendUint(b, uint64(vt), 10), nil
+	case uint16:
+		return strconv.AppendUint(b, uint64(vt), 10), nil
+	case uint32:
+		return strconv.AppendUint(b, uint64(vt), 10), nil
+	case uint64:
+		return strconv.AppendUint(b, vt, 10), nil
+	case float32:
+		return appendJsonFloat(b, float64(vt), 32), nil
+	case float64:
+		return appendJsonFloat(b, vt, 64), nil
+	case bool:
+		return strconv.AppendBool(b, vt), nil
+	case time.Duration:
+		return strconv.AppendInt(b, int64(vt), 10), nil
+	case time.Time:
+		return appendJsonTime(b, vt, time.RFC3339Nano), nil
+	case error:
+		return appendJsonString(b, vt.Error()), nil
+	case []string:
+		b = append(b, '[')
+		for i, s := range vt {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJsonString(b, s)
+		}
+		return append(b, ']'), nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return b, err
+	}
+	return append(b, data...), nil
+}
@@ -0,0 +1,215 @@
+package rogu
+
+import (
+	"strings"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+// FieldsLayout specifies how the fields of an
+// entry are written by the JsonWriter.
+type FieldsLayout int
+
+const (
+	// FieldsArray writes the fields as array of
+	// objects with `key` and `value` under the
+	// FieldsKey of the schema.
+	FieldsArray FieldsLayout = iota
+	// FieldsObject writes the fields as object
+	// under the FieldsKey of the schema.
+	FieldsObject
+	// FieldsFlat writes the fields as top-level
+	// keys of the entry.
+	FieldsFlat
+)
+
+// JsonSchema specifies the key names and the
+// layout of entries written by the JsonWriter.
+//
+// Components of an entry with an empty key are
+// not written.
+type JsonSchema struct {
+	TimestampKey   string
+	LevelKey       string
+	LevelStringKey string
+	MessageKey     string
+	ErrorKey       string
+	TagKey         string
+
+	// ErrorCausesKey is the key of the errors
+	// wrapped by the error of the entry. They are
+	// written as nested array of objects with the
+	// keys `message`, `type` and `causes`.
+	ErrorCausesKey string
+	// ErrorsKeyPrefix is prepended to the names of
+	// additional errors added via `Event.NamedErr`
+	// or `Event.Errs` to build their keys. Flat
+	// field keys starting with it are treated as
+	// collisions.
+	ErrorsKeyPrefix string
+
+	// CallerKey is the key of the caller. When
+	// CallerFileKey and CallerLineKey are set, the
+	// caller is written as object with those keys.
+	// Otherwise, it is written as "file:line" string.
+	//
+	// When CallerKey is empty but CallerFileKey and
+	// CallerLineKey are set, those are written as
+	// top-level keys.
+	CallerKey     string
+	CallerFileKey string
+	CallerLineKey string
+
+	// StackKey is the key of the stack trace. It is
+	// written as array of objects with the keys
+	// `function`, `file` and `line` or, when
+	// StackString is true, as string formatted like
+	// the stack traces of Go panics.
+	StackKey    string
+	StackString bool
+
+	// Fields specifies the layout of the fields.
+	Fields FieldsLayout
+	// FieldsKey is the key under which the fields
+	// are written when Fields is FieldsArray or
+	// FieldsObject.
+	FieldsKey string
+	// CollisionPrefix is prepended to the key of
+	// fields which collide with one of the keys of
+	// the schema when Fields is FieldsFlat.
+	CollisionPrefix string
+
+	// LevelString returns the value written under
+	// LevelStringKey. When nil, `level.Level.String`
+	// is used.
+	LevelString func(lvl level.Level) string
+}
+
+// The predefined schemas are shared. To customize
+// one of them, modify a copy like
+//
+//	schema := *rogu.JsonSchemaECS
+//	schema.CollisionPrefix = "fields."
+//	w.Schema = &schema
+var (
+	// JsonSchemaDefault is the default schema of
+	// the JsonWriter.
+	JsonSchemaDefault = &JsonSchema{
+		TimestampKey:    "timestamp",
+		LevelKey:        "level",
+		LevelStringKey:  "level_string",
+		MessageKey:      "message",
+		ErrorKey:        "error",
+		ErrorCausesKey:  "error.causes",
+		ErrorsKeyPrefix: "error.",
+		TagKey:          "tag",
+		CallerKey:       "caller",
+		CallerFileKey:   "file",
+		CallerLineKey:   "line",
+		StackKey:        "stack",
+		Fields:          FieldsArray,
+		FieldsKey:       "tags",
+	}
+
+	// JsonSchemaFlat is a generic schema writing
+	// all fields as top-level keys.
+	JsonSchemaFlat = &JsonSchema{
+		TimestampKey:    "time",
+		LevelStringKey:  "level",
+		MessageKey:      "msg",
+		ErrorKey:        "error",
+		ErrorCausesKey:  "error.causes",
+		ErrorsKeyPrefix: "error.",
+		TagKey:          "tag",
+		CallerKey:       "caller",
+		StackKey:        "stack",
+		Fields:          FieldsFlat,
+		CollisionPrefix: "fields.",
+	}
+
+	// JsonSchemaECS writes entries following the
+	// Elastic Common Schema.
+	//
+	// The TimeFormat of the JsonWriter should be
+	// set to time.RFC3339Nano.
+	JsonSchemaECS = &JsonSchema{
+		TimestampKey:    "@timestamp",
+		LevelStringKey:  "log.level",
+		MessageKey:      "message",
+		ErrorKey:        "error.message",
+		ErrorCausesKey:  "error.causes",
+		ErrorsKeyPrefix: "error.",
+		TagKey:          "log.logger",
+		CallerFileKey:   "log.origin.file.name",
+		CallerLineKey:   "log.origin.file.line",
+		StackKey:        "error.stack_trace",
+		StackString:     true,
+		Fields:          FieldsFlat,
+		CollisionPrefix: "labels.",
+	}
+
+	// JsonSchemaGCP writes entries as structured
+	// payload for Google Cloud Logging.
+	//
+	// The TimeFormat of the JsonWriter should be
+	// set to time.RFC3339Nano.
+	JsonSchemaGCP = &JsonSchema{
+		TimestampKey:    "time",
+		LevelStringKey:  "severity",
+		MessageKey:      "message",
+		ErrorKey:        "error",
+		ErrorCausesKey:  "error.causes",
+		ErrorsKeyPrefix: "error.",
+		TagKey:          "tag",
+		CallerKey:       "logging.googleapis.com/sourceLocation",
+		CallerFileKey:   "file",
+		CallerLineKey:   "line",
+		StackKey:        "stack_trace",
+		StackString:     true,
+		Fields:          FieldsFlat,
+		CollisionPrefix: "fields.",
+		LevelString:     gcpSeverity,
+	}
+)
+
+func (t *JsonSchema) levelString(lvl level.Level) string {
+	if t.LevelString != nil {
+		return t.LevelString(lvl)
+	}
+	return lvl.String()
+}
+
+// isReserved returns true if the given field key
+// collides with one of the keys of the schema or
+// may collide with the key of a named error.
+func (t *JsonSchema) isReserved(key string) bool {
+	switch key {
+	case "":
+		return false
+	case t.TimestampKey, t.LevelKey, t.LevelStringKey, t.MessageKey,
+		t.ErrorKey, t.ErrorCausesKey, t.TagKey, t.CallerKey, t.StackKey:
+		return true
+	}
+	if t.ErrorsKeyPrefix != "" && strings.HasPrefix(key, t.ErrorsKeyPrefix) {
+		return true
+	}
+	return t.CallerKey == "" && (key == t.CallerFileKey || key == t.CallerLineKey)
+}
+
+func gcpSeverity(lvl level.Level) string {
+	switch lvl {
+	case level.Panic:
+		return "ALERT"
+	case level.Fatal:
+		return "CRITICAL"
+	case level.Error:
+		return "ERROR"
+	case level.Warn:
+		return "WARNING"
+	case level.Info:
+		return "INFO"
+	case level.Debug, level.Trace:
+		return "DEBUG"
+	}
+	return "DEFAULT"
+}
@@ -1,23 +1,30 @@
 package rogu
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"sync"
 	"time"
-
-	"github.com/zekrotja/rogu/level"
 )
 
 // JsonWriter implements Writer for JSON
 // formatted entry output.
+//
+// The key names and the layout of the written
+// entries are specified by the Schema. When no
+// Schema is set, JsonSchemaDefault is used.
+//
+// NewJsonWriter sets a copy of JsonSchemaDefault,
+// so the schema of a writer can be modified without
+// affecting other writers.
 type JsonWriter struct {
 	writeMtx sync.Mutex
 
 	Output     io.Writer
 	TimeFormat string
+	Schema     *JsonSchema
 }
 
 var (
@@ -39,80 +46,265 @@ func NewJsonWriter(outputs ...io.Writer) *JsonWriter {
 	}
 
 	t.TimeFormat = time.RFC3339
+	schema := *JsonSchemaDefault
+	t.Schema = &schema
 
 	return &t
 }
 
-type field struct {
-	Key   interface{} `json:"key"`
-	Value interface{} `json:"value"`
-}
+func (t *JsonWriter) Write(e Entry) (err error) {
+	buf := bufferPool.Get()
+	defer func() {
+		if buf.Cap() == bufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
 
-type caller struct {
-	File string `json:"file,omitempty"`
-	Line int    `json:"line,omitempty"`
-}
+	b, err := t.appendEntry(buf.AvailableBuffer(), e)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
 
-type entry struct {
-	Timestamp string      `json:"timestamp,omitempty"`
-	Level     level.Level `json:"level"`
-	LevelStr  string      `json:"level_string"`
-	Tag       string      `json:"tag,omitempty"`
-	Message   string      `json:"message,omitempty"`
-	Error     string      `json:"error,omitempty"`
-	Fields    []field     `json:"tags,omitempty"`
-	Caller    caller      `json:"caller,omitempty"`
+	t.writeMtx.Lock()
+	defer t.writeMtx.Unlock()
+	_, err = t.Output.Write(buf.Bytes())
+	return err
 }
 
-func (t *JsonWriter) Write(
-	lvl level.Level,
-	fields []*Field,
-	tag string,
-	lErr error,
-	lErrFormat string,
-	callerFile string,
-	callerLine int,
-	msg string,
-) (err error) {
-	var e entry
-
-	e.Level = lvl
-	e.LevelStr = lvl.String()
-	e.Tag = tag
-	e.Message = msg
-
-	if t.TimeFormat != "" {
-		e.Timestamp = time.Now().Format(t.TimeFormat)
-	}
-
-	if lErr != nil {
-		if lErrFormat != "" {
-			e.Error = fmt.Sprintf(lErrFormat, lErr)
-		} else {
-			e.Error = lErr.Error()
+// appendEntry appends the entry as JSON object
+// followed by a newline to b.
+func (t *JsonWriter) appendEntry(b []byte, e Entry) (_ []byte, err error) {
+	schema := t.Schema
+	if schema == nil {
+		schema = JsonSchemaDefault
+	}
+
+	b = append(b, '{')
+
+	if schema.TimestampKey != "" && t.TimeFormat != "" && !e.Time.IsZero() {
+		b = appendJsonNextKey(b, schema.TimestampKey)
+		b = appendJsonTime(b, e.Time, t.TimeFormat)
+	}
+
+	if schema.LevelKey != "" {
+		b = appendJsonNextKey(b, schema.LevelKey)
+		b = strconv.AppendInt(b, int64(e.Level), 10)
+	}
+
+	if schema.LevelStringKey != "" {
+		b = appendJsonNextKey(b, schema.LevelStringKey)
+		b = appendJsonString(b, schema.levelString(e.Level))
+	}
+
+	if schema.TagKey != "" && e.Tag != "" {
+		b = appendJsonNextKey(b, schema.TagKey)
+		b = appendJsonString(b, e.Tag)
+	}
+
+	if schema.MessageKey != "" && e.Message != "" {
+		b = appendJsonNextKey(b, schema.MessageKey)
+		b = appendJsonString(b, e.Message)
+	}
+
+	if schema.ErrorKey != "" && e.Err != nil {
+		b = appendJsonNextKey(b, schema.ErrorKey)
+		b = appendJsonString(b, e.ErrString())
+	}
+
+	if schema.ErrorCausesKey != "" && e.Err != nil {
+		if causes := unwrapErrors(e.Err); len(causes) > 0 {
+			b = appendJsonNextKey(b, schema.ErrorCausesKey)
+			b = appendJsonErrors(b, causes, 0)
+		}
+	}
+
+	if schema.ErrorsKeyPrefix != "" {
+		for _, ne := range e.Errs {
+			b = appendJsonNextKey(b, schema.ErrorsKeyPrefix+ne.Name)
+			b = appendJsonString(b, ne.String())
+
+			if schema.ErrorCausesKey == "" {
+				continue
+			}
+			if causes := unwrapErrors(ne.Err); len(causes) > 0 {
+				b = appendJsonNextKey(b, schema.ErrorsKeyPrefix+ne.Name+".causes")
+				b = appendJsonErrors(b, causes, 0)
+			}
+		}
+	}
+
+	if len(e.Fields) > 0 {
+		if b, err = t.appendFields(b, schema, e.Fields); err != nil {
+			return b, err
+		}
+	}
+
+	if e.CallerFile != "" {
+		b = t.appendCaller(b, schema, e.CallerFile, e.CallerLine)
+	}
+
+	if schema.StackKey != "" && len(e.Stack) > 0 {
+		b = t.appendStack(b, schema, schema.StackKey, e.Stack)
+	}
+
+	if schema.StackKey != "" && schema.ErrorsKeyPrefix != "" {
+		for _, ne := range e.Errs {
+			if len(ne.Stack) > 0 {
+				b = t.appendStack(b, schema, schema.ErrorsKeyPrefix+ne.Name+".stack", ne.Stack)
+			}
 		}
 	}
 
-	if len(fields) > 0 {
-		e.Fields = make([]field, 0, len(fields))
+	return append(b, '}', '\n'), nil
+}
+
+func (t *JsonWriter) appendFields(b []byte, schema *JsonSchema, fields []*Field) (_ []byte, err error) {
+	switch schema.Fields {
+	case FieldsFlat:
+		for _, f := range fields {
+			if key := f.KeyString(); schema.isReserved(key) {
+				b = appendJsonNextKey(b, schema.CollisionPrefix+key)
+			} else {
+				b = appendJsonNextKey(b, key)
+			}
+			if b, err = appendJsonField(b, f); err != nil {
+				return b, err
+			}
+		}
+
+	case FieldsObject:
+		if schema.FieldsKey == "" {
+			return b, nil
+		}
+		b = appendJsonNextKey(b, schema.FieldsKey)
+		b = append(b, '{')
 		for _, f := range fields {
-			e.Fields = append(e.Fields, field{
-				Key:   f.Key,
-				Value: f.Val,
-			})
+			b = appendJsonNextKey(b, f.KeyString())
+			if b, err = appendJsonField(b, f); err != nil {
+				return b, err
+			}
+		}
+		b = append(b, '}')
+
+	default:
+		if schema.FieldsKey == "" {
+			return b, nil
 		}
+		b = appendJsonNextKey(b, schema.FieldsKey)
+		b = append(b, '[')
+		for i, f := range fields {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, '{')
+			b = appendJsonKey(b, "key")
+			b = appendJsonString(b, f.KeyString())
+			b = append(b, ',')
+			b = appendJsonKey(b, "value")
+			if b, err = appendJsonField(b, f); err != nil {
+				return b, err
+			}
+			b = append(b, '}')
+		}
+		b = append(b, ']')
+	}
+
+	return b, nil
+}
+
+func (t *JsonWriter) appendCaller(b []byte, schema *JsonSchema, file string, line int) []byte {
+	hasKeys := schema.CallerFileKey != "" && schema.CallerLineKey != ""
+
+	switch {
+	case schema.CallerKey != "" && hasKeys:
+		b = appendJsonNextKey(b, schema.CallerKey)
+		b = append(b, '{')
+		b = appendJsonKey(b, schema.CallerFileKey)
+		b = appendJsonString(b, file)
+		b = append(b, ',')
+		b = appendJsonKey(b, schema.CallerLineKey)
+		b = strconv.AppendInt(b, int64(line), 10)
+		b = append(b, '}')
+
+	case schema.CallerKey != "":
+		b = appendJsonNextKey(b, schema.CallerKey)
+		var cb [256]byte
+		c := append(cb[:0], file...)
+		c = append(c, ':')
+		c = strconv.AppendInt(c, int64(line), 10)
+		b = appendJsonString(b, c)
+
+	case hasKeys:
+		b = appendJsonNextKey(b, schema.CallerFileKey)
+		b = appendJsonString(b, file)
+		b = appendJsonNextKey(b, schema.CallerLineKey)
+		b = strconv.AppendInt(b, int64(line), 10)
 	}
 
-	if callerFile != "" {
-		e.Caller = caller{
-			File: callerFile,
-			Line: callerLine,
+	return b
+}
+
+// appendJsonErrors appends errs as array of
+// objects containing the message, type and
+// causes of each error.
+func appendJsonErrors(b []byte, errs []error, depth int) []byte {
+	b = append(b, '[')
+	for i, err := range errs {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if err == nil {
+			b = append(b, "null"...)
+			continue
+		}
+		b = append(b, '{')
+		b = appendJsonKey(b, "message")
+		b = appendJsonString(b, err.Error())
+		b = append(b, ',')
+		b = appendJsonKey(b, "type")
+		b = appendJsonString(b, fmt.Sprintf("%T", err))
+		if causes := unwrapErrors(err); len(causes) > 0 && depth < maxErrorDepth {
+			b = append(b, ',')
+			b = appendJsonKey(b, "causes")
+			b = appendJsonErrors(b, causes, depth+1)
 		}
+		b = append(b, '}')
 	}
+	return append(b, ']')
+}
 
-	t.writeMtx.Lock()
-	defer t.writeMtx.Unlock()
-	return json.NewEncoder(t.Output).Encode(e)
+func (t *JsonWriter) appendStack(b []byte, schema *JsonSchema, key string, stack []Frame) []byte {
+	b = appendJsonNextKey(b, key)
+
+	if schema.StackString {
+		sb := bufferPool.Get()
+		defer func() {
+			if sb.Cap() == bufferSize {
+				bufferPool.Put(sb)
+			}
+		}()
+		s := appendStackString(sb.AvailableBuffer(), stack)
+		return appendJsonString(b, s)
+	}
+
+	b = append(b, '[')
+	for i, f := range stack {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '{')
+		b = appendJsonKey(b, "function")
+		b = appendJsonString(b, f.Function)
+		b = append(b, ',')
+		b = appendJsonKey(b, "file")
+		b = appendJsonString(b, f.File)
+		b = append(b, ',')
+		b = appendJsonKey(b, "line")
+		b = strconv.AppendInt(b, int64(f.Line), 10)
+		b = append(b, '}')
+	}
+	return append(b, ']')
 }
 
 func (t *JsonWriter) Close() error {
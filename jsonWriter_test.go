@@ -0,0 +1,224 @@
+package rogu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestJsonWriter(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(NewJsonWriter(&buf)).SetCaller(true)
+
+	l.Warn().
+		Tag("tag").
+		Err(errors.New("some error")).
+		Str("str", "quote \" backslash \\ newline \n tab \t ctrl \x01 invalid \xff sep \u2028").
+		Int("int", -42).
+		Float("nan", math.NaN()).
+		Strs("strs", []string{"a", "b"}).
+		Field("map", map[string]int{"a": 1}).
+		Field("struct", struct{ A int }{A: 1}).
+		Msg("hello \"world\"")
+
+	var res struct {
+		Timestamp string      `json:"timestamp"`
+		Level     level.Level `json:"level"`
+		LevelStr  string      `json:"level_string"`
+		Tag       string      `json:"tag"`
+		Message   string      `json:"message"`
+		Error     string      `json:"error"`
+		Fields    []struct {
+			Key   string `json:"key"`
+			Value any    `json:"value"`
+		} `json:"tags"`
+		Caller struct {
+			File string `json:"file"`
+			Line int    `json:"line"`
+		} `json:"caller"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+
+	if _, err := time.Parse(time.RFC3339, res.Timestamp); err != nil {
+		t.Errorf("invalid timestamp: %s", err)
+	}
+	if res.Level != level.Warn || res.LevelStr != "warn" || res.Tag != "tag" ||
+		res.Message != `hello "world"` || res.Error != "some error" {
+		t.Errorf("unexpected entry: %+v", res)
+	}
+	if res.Caller.File == "" || res.Caller.Line == 0 {
+		t.Errorf("missing caller: %+v", res.Caller)
+	}
+
+	exp := []any{
+		"quote \" backslash \\ newline \n tab \t ctrl \x01 invalid \ufffd sep \u2028",
+		float64(-42),
+		"NaN",
+		[]any{"a", "b"},
+		map[string]any{"a": float64(1)},
+		map[string]any{"A": float64(1)},
+	}
+	if len(res.Fields) != len(exp) {
+		t.Fatalf("unexpected fields: %+v", res.Fields)
+	}
+	for i, f := range res.Fields {
+		got, _ := json.Marshal(f.Value)
+		want, _ := json.Marshal(exp[i])
+		if !bytes.Equal(got, want) {
+			t.Errorf("unexpected value of %s: %s, expected %s", f.Key, got, want)
+		}
+	}
+}
+
+func BenchmarkJsonWriter(b *testing.B) {
+	l := NewLogger(NewJsonWriter(io.Discard))
+
+	b.Run("single-message", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info().Msg("bench")
+		}
+	})
+
+	b.Run("fields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info().Field("str", "str").Field("int", i).Msg("bench")
+		}
+	})
+
+	b.Run("typed-fields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info().Str("str", "str").Int("int", i).Msg("bench")
+		}
+	})
+
+	b.Run("object-field", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info().Object("obj", struct{ A, B int }{i, i}).Msg("bench")
+		}
+	})
+}
+
+func TestJsonWriterSchemas(t *testing.T) {
+	write := func(schema *JsonSchema) map[string]any {
+		t.Helper()
+
+		var buf bytes.Buffer
+		w := NewJsonWriter(&buf)
+		w.Schema = schema
+
+		NewLogger(w).SetCaller(true).
+			Error().
+			Tag("db").
+			Err(errors.New("failed")).
+			Str("message", "collides").
+			Int("attempt", 3).
+			Msg("query failed")
+
+		var res map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+			t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+		}
+		return res
+	}
+
+	ecs := write(JsonSchemaECS)
+	for k, v := range map[string]any{
+		"log.level":            "error",
+		"log.logger":           "db",
+		"message":              "query failed",
+		"error.message":        "failed",
+		"labels.message":       "collides",
+		"attempt":              float64(3),
+		"log.origin.file.name": ecs["log.origin.file.name"],
+	} {
+		if ecs[k] != v || v == nil {
+			t.Errorf("ECS: unexpected value of %s: %v", k, ecs[k])
+		}
+	}
+	if _, ok := ecs["@timestamp"]; !ok {
+		t.Error("ECS: missing timestamp")
+	}
+
+	gcp := write(JsonSchemaGCP)
+	if gcp["severity"] != "ERROR" || gcp["fields.message"] != "collides" {
+		t.Errorf("GCP: unexpected entry: %v", gcp)
+	}
+	if loc, _ := gcp["logging.googleapis.com/sourceLocation"].(map[string]any); loc["file"] == nil || loc["line"] == nil {
+		t.Errorf("GCP: unexpected source location: %v", loc)
+	}
+
+	flat := write(JsonSchemaFlat)
+	if flat["msg"] != "query failed" || flat["message"] != "collides" || flat["attempt"] != float64(3) {
+		t.Errorf("flat: unexpected entry: %v", flat)
+	}
+	if caller, _ := flat["caller"].(string); !strings.Contains(caller, "jsonWriter_test.go:") {
+		t.Errorf("flat: unexpected caller: %v", flat["caller"])
+	}
+
+	var buf bytes.Buffer
+	fw := NewJsonWriter(&buf)
+	fw.Schema = JsonSchemaFlat
+	NewLogger(fw).Error().NamedErr("db", errors.New("failed")).Str("error.db", "collides").Msg("hello")
+	if n := strings.Count(buf.String(), `"error.db"`); n != 1 || !strings.Contains(buf.String(), `"fields.error.db":"collides"`) {
+		t.Errorf("flat: field collides with named error: %s", buf.String())
+	}
+
+	obj := write(&JsonSchema{MessageKey: "msg", Fields: FieldsObject, FieldsKey: "fields"})
+	if len(obj) != 2 {
+		t.Errorf("object: unexpected keys: %v", obj)
+	}
+	if fields, _ := obj["fields"].(map[string]any); fields["attempt"] != float64(3) {
+		t.Errorf("object: unexpected fields: %v", obj["fields"])
+	}
+}
+
+func TestJsonWriterSchemaCopy(t *testing.T) {
+	w1, w2 := NewJsonWriter(io.Discard), NewJsonWriter(io.Discard)
+	w1.Schema.MessageKey = "msg"
+
+	if JsonSchemaDefault.MessageKey != "message" || w2.Schema.MessageKey != "message" {
+		t.Error("modifying the schema of a writer affects the default schema")
+	}
+
+	NewNDJSONEncoder().Schema.TimestampKey = "ts"
+	NewElasticsearchEncoder("logs").Schema.TimestampKey = "ts"
+	if JsonSchemaFlat.TimestampKey != "time" || JsonSchemaECS.TimestampKey != "@timestamp" {
+		t.Error("modifying the schema of an encoder affects the predefined schema")
+	}
+}
+
+type nameStringer struct{ name string }
+
+func (t *nameStringer) String() string { return t.name }
+
+func TestJsonWriterValues(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJsonWriter(&buf)
+	w.Schema = JsonSchemaFlat
+
+	NewLogger(w).Info().
+		Field("f32", float32(0.1)).
+		Stringer("nil", (*nameStringer)(nil)).
+		Stringer("name", &nameStringer{"a"}).
+		Msg("hello")
+
+	for _, s := range []string{`"f32":0.1,`, `"nil":null`, `"name":"a"`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("missing %s in %s", s, buf.String())
+		}
+	}
+}
@@ -45,6 +45,9 @@ func (l Level) String() string {
 // LevelFromString tries to get a Level from the
 // given string.
 //
+// Besides the names of the levels, "off" and
+// "all" are accepted.
+//
 // ok is false if no level could be matched
 // with the passed stirng.
 func LevelFromString(v string) (lvl Level, ok bool) {
@@ -59,6 +62,10 @@ func LevelFromString(v string) (lvl Level, ok bool) {
 	ok = true
 
 	switch strings.ToLower(v) {
+	case "off":
+		lvl = Off
+	case "all":
+		lvl = All
 	case "p", "pnc", "panic":
 		lvl = Panic
 	case "f", "ftl", "fatal":
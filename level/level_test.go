@@ -36,6 +36,14 @@ func TestLevelFromString(t *testing.T) {
 	assertLvl(t, "7", Trace)
 }
 
+func TestLevelFromStringOffAll(t *testing.T) {
+	for v, exp := range map[string]Level{"off": Off, "OFF": Off, "all": All, "All": All} {
+		if lvl, ok := LevelFromString(v); !ok || lvl != exp {
+			t.Errorf("%s: got %d (%t); want %d", v, lvl, ok, exp)
+		}
+	}
+}
+
 func assertLvl(t *testing.T, v string, exp Level) {
 	t.Helper()
 
@@ -0,0 +1,229 @@
+package rogu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+// LevelHandler implements http.Handler to read and
+// change the levels of a Logger at runtime.
+//
+// A GET request responds with the current global
+// and per-tag levels like
+//
+//	{"level":"info","tags":{"Database":"trace"}}
+//
+// A PUT request with a body of the same format sets
+// the given levels. Levels can be passed as any
+// value accepted by `level.LevelFromString`, which
+// includes "off" and "all". A tag set to an empty string
+// is removed. Tags which are not passed are kept.
+//
+// When the body contains a "ttl" like "10m", the
+// levels are reverted to the state before the
+// change after the given duration. Changes made
+// while a revert is pending cancel the pending
+// revert. In case the change has a TTL as well,
+// it reverts to the state before the first change.
+//
+// Mind that the handler does not perform any
+// authentication.
+type LevelHandler struct {
+	l Logger
+
+	mtx         sync.Mutex
+	revertTimer *time.Timer
+	revertGen   uint64
+	revertTo    *levelState
+}
+
+var _ http.Handler = (*LevelHandler)(nil)
+
+type levelState struct {
+	lvl  level.Level
+	tags map[string]level.Level
+}
+
+type levelRequest struct {
+	Level string            `json:"level,omitempty"`
+	Tags  map[string]string `json:"tags,omitempty"`
+	TTL   string            `json:"ttl,omitempty"`
+}
+
+type levelResponse struct {
+	Level string            `json:"level"`
+	Tags  map[string]string `json:"tags"`
+}
+
+// NewLevelHandler returns a new LevelHandler
+// reading and changing the levels of l.
+func NewLevelHandler(l Logger) *LevelHandler {
+	return &LevelHandler{l: l}
+}
+
+func (t *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		t.writeLevels(w)
+	case http.MethodPut:
+		if err := t.update(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		t.writeLevels(w)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (t *LevelHandler) update(r *http.Request) error {
+	var req levelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	var (
+		lvl    level.Level
+		hasLvl bool
+		ttl    time.Duration
+		err    error
+	)
+
+	if req.Level != "" {
+		if lvl, hasLvl = level.LevelFromString(req.Level); !hasLvl {
+			return fmt.Errorf("invalid level: %s", req.Level)
+		}
+	}
+
+	tags := make(map[string]level.Level, len(req.Tags))
+	for tag, v := range req.Tags {
+		if v == "" {
+			continue
+		}
+		l, ok := level.LevelFromString(v)
+		if !ok {
+			return fmt.Errorf("invalid level of tag %s: %s", tag, v)
+		}
+		tags[tag] = l
+	}
+
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid ttl: %s", req.TTL)
+		}
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	current := t.state()
+
+	if t.revertTimer != nil {
+		t.revertTimer.Stop()
+		t.revertTimer = nil
+	}
+	if ttl == 0 {
+		t.revertTo = nil
+	} else if t.revertTo == nil {
+		t.revertTo = current
+	}
+
+	// current might be kept as revert state, so
+	// the new tag levels are set on a copy.
+	newTags := make(map[string]level.Level, len(current.tags)+len(req.Tags))
+	for tag, lvl := range current.tags {
+		newTags[tag] = lvl
+	}
+	for tag, v := range req.Tags {
+		if v == "" {
+			delete(newTags, tag)
+		} else {
+			newTags[tag] = tags[tag]
+		}
+	}
+	t.setLevels(newTags, lvl, hasLvl)
+
+	if ttl > 0 {
+		t.revertGen++
+		gen := t.revertGen
+		t.revertTimer = time.AfterFunc(ttl, func() {
+			t.revert(gen)
+		})
+	}
+
+	return nil
+}
+
+func (t *LevelHandler) revert(gen uint64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	// The timer might have been replaced while
+	// this function was waiting for the lock.
+	if t.revertGen != gen || t.revertTimer == nil || t.revertTo == nil {
+		return
+	}
+
+	t.setLevels(t.revertTo.tags, t.revertTo.lvl, true)
+	t.revertTimer = nil
+	t.revertTo = nil
+}
+
+// setLevels applies the tag levels and, if setLvl
+// is true, the level to the logger as a single
+// change.
+func (t *LevelHandler) setLevels(tags map[string]level.Level, lvl level.Level, setLvl bool) {
+	if ls, ok := t.l.(interface {
+		setLevels(tags map[string]level.Level, lvl level.Level, setLvl bool)
+	}); ok {
+		ls.setLevels(tags, lvl, setLvl)
+		return
+	}
+
+	t.l.SetTagLevels(tags)
+	if setLvl {
+		t.l.SetLevel(lvl)
+	}
+}
+
+func (t *LevelHandler) state() *levelState {
+	return &levelState{
+		lvl:  t.l.Level(),
+		tags: t.l.TagLevels(),
+	}
+}
+
+func (t *LevelHandler) writeLevels(w http.ResponseWriter) {
+	s := t.state()
+
+	res := levelResponse{
+		Level: levelName(s.lvl),
+		Tags:  make(map[string]string, len(s.tags)),
+	}
+	for tag, lvl := range s.tags {
+		res.Tags[tag] = levelName(lvl)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func levelName(lvl level.Level) string {
+	switch lvl {
+	case level.Off:
+		return "off"
+	case level.All:
+		return "all"
+	}
+	if s := lvl.String(); s != "" {
+		return s
+	}
+	return strconv.Itoa(int(lvl))
+}
@@ -0,0 +1,182 @@
+package rogu
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func doLevelRequest(t *testing.T, h http.Handler, method, body string) (int, levelResponse) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, "/levels", strings.NewReader(body)))
+
+	var res levelResponse
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, res
+}
+
+func TestLevelHandler(t *testing.T) {
+	l := NewLogger(nopWriter{}).SetTagLevel("Database", level.Trace)
+	h := NewLevelHandler(l)
+
+	code, res := doLevelRequest(t, h, http.MethodGet, "")
+	if code != http.StatusOK || res.Level != "info" || res.Tags["Database"] != "trace" {
+		t.Errorf("unexpected response: %d %+v", code, res)
+	}
+
+	code, res = doLevelRequest(t, h, http.MethodPut,
+		`{"level":"debug","tags":{"Database":"","http":"warn"}}`)
+	if code != http.StatusOK || res.Level != "debug" || len(res.Tags) != 1 || res.Tags["http"] != "warn" {
+		t.Errorf("unexpected response: %d %+v", code, res)
+	}
+	if l.Level() != level.Debug {
+		t.Errorf("unexpected level: %s", l.Level())
+	}
+
+	for _, body := range []string{`{"level":"loud"}`, `{"tags":{"a":"x"}}`, `{"ttl":"-1s"}`, `nope`} {
+		if code, _ = doLevelRequest(t, h, http.MethodPut, body); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, code)
+		}
+	}
+
+	if code, _ = doLevelRequest(t, h, http.MethodPost, ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", code)
+	}
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	l := NewLogger(nopWriter{})
+	h := NewLevelHandler(l)
+
+	doLevelRequest(t, h, http.MethodPut, `{"level":"debug","ttl":"1h"}`)
+	doLevelRequest(t, h, http.MethodPut, `{"level":"trace","tags":{"db":"error"},"ttl":"20ms"}`)
+	if l.Level() != level.Trace {
+		t.Fatalf("unexpected level: %s", l.Level())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for l.Level() != level.Info {
+		if time.Now().After(deadline) {
+			t.Fatalf("level has not been reverted: %s", l.Level())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if tags := l.TagLevels(); len(tags) != 0 {
+		t.Errorf("tag levels have not been reverted: %v", tags)
+	}
+}
+
+func TestLevelHandlerTTLTags(t *testing.T) {
+	l := NewLogger(nopWriter{}).SetTagLevel("http", level.Warn)
+	h := NewLevelHandler(l)
+
+	doLevelRequest(t, h, http.MethodPut, `{"tags":{"db":"trace","http":""},"ttl":"20ms"}`)
+	if tags := l.TagLevels(); len(tags) != 1 || tags["db"] != level.Trace {
+		t.Fatalf("unexpected tag levels: %v", tags)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		tags := l.TagLevels()
+		if len(tags) == 1 && tags["http"] == level.Warn {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("tag levels have not been reverted: %v", tags)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCycleLevel(t *testing.T) {
+	l := NewLogger(nopWriter{}).SetLevel(level.Info)
+
+	expected := []level.Level{level.Debug, level.Trace, level.Info, level.Debug}
+	for _, lvl := range expected {
+		cycleLevel(l, level.Info)
+		if l.Level() != lvl {
+			t.Errorf("expected %s, got %s", lvl, l.Level())
+		}
+	}
+}
+
+func TestLevelChangesConcurrent(t *testing.T) {
+	l := NewLogger(nopWriter{})
+	tagged := l.Tagged("http.router")
+	h := NewLevelHandler(l)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for _, lg := range []Logger{l, tagged} {
+		wg.Add(1)
+		go func(lg Logger) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					lg.Debug().Str("k", "v").Msg("hello")
+					lg.Info().Tag("Database").Msg("hello")
+				}
+			}
+		}(lg)
+	}
+
+	for i := 0; i < 100; i++ {
+		doLevelRequest(t, h, http.MethodPut, `{"level":"trace","tags":{"http":"debug"},"ttl":"1ms"}`)
+		doLevelRequest(t, h, http.MethodGet, "")
+		cycleLevel(l, level.Info)
+		l.SetTagLevel("Database", level.Warn)
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestLevelHandlerUpdateAtomic(t *testing.T) {
+	l := NewLogger(nopWriter{}).Copy()
+	h := NewLevelHandler(l)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			c := l.cfg.Load()
+			tagLvl, ok := c.tags.lookup("db")
+			if (c.lvl == level.Debug) != (ok && tagLvl == level.Trace) {
+				t.Errorf("half applied update: level %s, db %s (%t)", c.lvl, tagLvl, ok)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		doLevelRequest(t, h, http.MethodPut, `{"level":"debug","tags":{"db":"trace"}}`)
+		doLevelRequest(t, h, http.MethodPut, `{"level":"info","tags":{"db":""}}`)
+	}
+
+	close(stop)
+	wg.Wait()
+}
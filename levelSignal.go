@@ -0,0 +1,67 @@
+package rogu
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+// CycleLevelOnSignal increases the verbosity of l
+// by one level every time one of the given signals
+// is received. After level.Trace, the level wraps
+// around to the level l had when this function was
+// called. When no signal is specified, SIGUSR1 is
+// used on unix systems.
+//
+// The returned function stops listening for
+// the signals.
+//
+// Example:
+//
+//	stop := rogu.CycleLevelOnSignal(l)
+//	defer stop()
+//
+//	// $ kill -USR1 <pid>
+func CycleLevelOnSignal(l Logger, sig ...os.Signal) (stop func()) {
+	if len(sig) == 0 {
+		sig = defaultCycleSignals
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	if len(sig) > 0 {
+		signal.Notify(ch, sig...)
+	}
+
+	base := l.Level()
+	go func() {
+		for {
+			select {
+			case <-ch:
+				cycleLevel(l, base)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// cycleLevel sets the level of l to the next
+// more verbose level or to base after level.Trace.
+func cycleLevel(l Logger, base level.Level) {
+	next := l.Level() + 1
+	if next > level.Trace || next <= base {
+		next = base
+	}
+	l.SetLevel(next)
+}
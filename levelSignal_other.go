@@ -0,0 +1,7 @@
+//go:build !unix
+
+package rogu
+
+import "os"
+
+var defaultCycleSignals []os.Signal
@@ -0,0 +1,36 @@
+//go:build unix
+
+package rogu
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestCycleLevelOnSignal(t *testing.T) {
+	l := NewLogger(nopWriter{}).SetLevel(level.Info)
+
+	stop := CycleLevelOnSignal(l)
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for l.Level() != level.Debug {
+		if time.Now().After(deadline) {
+			t.Fatalf("level has not been changed: %s", l.Level())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCycleLevelOnSignalStopTwice(t *testing.T) {
+	stop := CycleLevelOnSignal(NewLogger(nopWriter{}))
+	stop()
+	stop()
+}
@@ -0,0 +1,10 @@
+//go:build unix
+
+package rogu
+
+import (
+	"os"
+	"syscall"
+)
+
+var defaultCycleSignals = []os.Signal{syscall.SIGUSR1}
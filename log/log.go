@@ -18,18 +18,77 @@ func AddWriter(w rogu.Writer) rogu.Logger {
 	return defaultLogger.AddWriter(w)
 }
 
+// AddContextExtractor registers a ContextExtractor
+// which is applied to the context of every event
+// passed via `Event.Ctx`.
+func AddContextExtractor(ex rogu.ContextExtractor) rogu.Logger {
+	return defaultLogger.AddContextExtractor(ex)
+}
+
+// AddHook registers a Hook which is applied to
+// every commited event before it is passed to the
+// writer.
+func AddHook(h rogu.Hook) rogu.Logger {
+	return defaultLogger.AddHook(h)
+}
+
 // SetLevel sets the minum log leven which
 // will be written.
 func SetLevel(lvl level.Level) rogu.Logger {
 	return defaultLogger.SetLevel(lvl)
 }
 
+// Level returns the minimum log level set
+// via `SetLevel`.
+func Level() level.Level {
+	return defaultLogger.Level()
+}
+
+// SetTagLevel sets the minimum log level for
+// events with a tag matching the given pattern.
+func SetTagLevel(pattern string, lvl level.Level) rogu.Logger {
+	return defaultLogger.SetTagLevel(pattern, lvl)
+}
+
+// UnsetTagLevel removes the level set for the
+// given tag pattern via `SetTagLevel`.
+func UnsetTagLevel(pattern string) rogu.Logger {
+	return defaultLogger.UnsetTagLevel(pattern)
+}
+
+// SetTagLevels replaces all levels set per tag
+// pattern with the given levels.
+func SetTagLevels(levels map[string]level.Level) rogu.Logger {
+	return defaultLogger.SetTagLevels(levels)
+}
+
 // SetCaller enabled or disables attaching the
 // caller file and line to the event.
 func SetCaller(enable bool) rogu.Logger {
 	return defaultLogger.SetCaller(enable)
 }
 
+// SetStackLevel sets the least severe level of
+// events which carry a stack trace without
+// calling `Event.Stack`.
+func SetStackLevel(lvl level.Level) rogu.Logger {
+	return defaultLogger.SetStackLevel(lvl)
+}
+
+// SetExitFunc sets the function which is called
+// with the exit code after a fatal event has been
+// commited. Defaults to os.Exit.
+func SetExitFunc(fn func(code int)) rogu.Logger {
+	return defaultLogger.SetExitFunc(fn)
+}
+
+// SetPanicFunc sets the function which is called
+// with the message after a panic event has been
+// commited.
+func SetPanicFunc(fn func(msg string)) rogu.Logger {
+	return defaultLogger.SetPanicFunc(fn)
+}
+
 // Copy creates and returns a copy of the Logger.
 func Copy() rogu.Logger {
 	return defaultLogger.Copy()
@@ -44,6 +103,15 @@ func Tagged(tag string) rogu.Logger {
 	return defaultLogger.Tagged(tag)
 }
 
+// With returns a new logger which references
+// the origin logger but adds the given fields
+// to every created Entry. Changes made to the
+// underlying logger will be projected to the
+// created logger.
+func With(kv ...any) rogu.Logger {
+	return defaultLogger.With(kv...)
+}
+
 func Close() error {
 	return defaultLogger.Close()
 }
@@ -75,8 +143,9 @@ func Error() *rogu.Event {
 
 // Trace creates a new log Event with level fatal.
 //
-// When commited, the programm will exit with exit
-// code 1.
+// When commited, all writers are closed and the
+// programm will exit with exit code 1 or the code
+// set via `Event.ExitCode`.
 func Fatal() *rogu.Event {
 	return defaultLogger.Fatal()
 }
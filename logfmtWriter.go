@@ -0,0 +1,282 @@
+package rogu
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LogfmtWriter implements Writer for logfmt
+// formatted entry output like
+//
+//	ts=2006-01-02T15:04:05Z level=info tag=db msg="hello world" key=value
+//
+// Slice and map field values are flattened into
+// keys with the index or map key attached, for
+// example `params.0=foo` or `a_map.bazz=bar`.
+//
+// With setting TimeFormat you specify the format of
+// the timestamp and time.Time field values. When
+// TimeFormat is set to an empty string, no
+// timestamp will be written.
+type LogfmtWriter struct {
+	writeMtx sync.Mutex
+
+	Output     io.Writer
+	TimeFormat string
+}
+
+var (
+	_ Writer = (*LogfmtWriter)(nil)
+	_ Closer = (*LogfmtWriter)(nil)
+)
+
+// NewLogfmtWriter returns a new LogfmtWriter
+// with the passed target output writers. When
+// no writers are specified, os.Stdout will be
+// used.
+func NewLogfmtWriter(outputs ...io.Writer) *LogfmtWriter {
+	var t LogfmtWriter
+
+	if len(outputs) == 0 {
+		t.Output = os.Stdout
+	} else if len(outputs) == 1 {
+		t.Output = outputs[0]
+	} else {
+		t.Output = io.MultiWriter(outputs...)
+	}
+
+	t.TimeFormat = time.RFC3339
+
+	return &t
+}
+
+func (t *LogfmtWriter) Write(e Entry) (err error) {
+	buf := bufferPool.Get()
+	defer func() {
+		if buf.Cap() == bufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	buf.Write(t.appendEntry(buf.AvailableBuffer(), e))
+
+	t.writeMtx.Lock()
+	defer t.writeMtx.Unlock()
+	_, err = t.Output.Write(buf.Bytes())
+	return err
+}
+
+// appendEntry appends the entry as logfmt line
+// followed by a newline to b.
+func (t *LogfmtWriter) appendEntry(b []byte, e Entry) []byte {
+	if t.TimeFormat != "" && !e.Time.IsZero() {
+		var tb [64]byte
+		b = appendLogfmtKey(b, "ts")
+		b = appendLogfmtValue(b, e.Time.AppendFormat(tb[:0], t.TimeFormat))
+	}
+
+	b = appendLogfmtKey(b, "level")
+	b = append(b, e.Level.String()...)
+
+	if e.CallerFile != "" {
+		var cb [256]byte
+		c := append(cb[:0], filepath.Base(e.CallerFile)...)
+		c = append(c, ':')
+		c = strconv.AppendInt(c, int64(e.CallerLine), 10)
+		b = appendLogfmtKey(b, "caller")
+		b = appendLogfmtValue(b, c)
+	}
+
+	if e.Tag != "" {
+		b = appendLogfmtKey(b, "tag")
+		b = appendLogfmtValue(b, e.Tag)
+	}
+
+	b = appendLogfmtKey(b, "msg")
+	b = appendLogfmtValue(b, e.Message)
+
+	if e.Err != nil {
+		b = appendLogfmtKey(b, "error")
+		b = appendLogfmtValue(b, e.ErrString())
+	}
+
+	for _, ne := range e.Errs {
+		b = appendLogfmtKey(b, "error."+ne.Name)
+		b = appendLogfmtValue(b, ne.String())
+	}
+
+	for _, f := range e.Fields {
+		b = t.appendField(b, f)
+	}
+
+	return append(b, '\n')
+}
+
+func (t *LogfmtWriter) Close() error {
+	if c, ok := t.Output.(Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (t *LogfmtWriter) appendField(b []byte, f *Field) []byte {
+	key := f.KeyString()
+
+	switch f.kind {
+	case KindString:
+		return appendLogfmtValue(appendLogfmtKey(b, key), f.str)
+	case KindInt64:
+		return strconv.AppendInt(appendLogfmtKey(b, key), f.AsInt64(), 10)
+	case KindUint64:
+		return strconv.AppendUint(appendLogfmtKey(b, key), f.num, 10)
+	case KindFloat64:
+		return strconv.AppendFloat(appendLogfmtKey(b, key), f.AsFloat64(), 'g', -1, 64)
+	case KindBool:
+		return strconv.AppendBool(appendLogfmtKey(b, key), f.AsBool())
+	case KindDuration:
+		return append(appendLogfmtKey(b, key), f.AsDuration().String()...)
+	case KindTime:
+		return t.appendTime(appendLogfmtKey(b, key), f.tm)
+	case KindBytes:
+		return appendLogfmtValue(appendLogfmtKey(b, key), f.byts)
+	case KindStrings:
+		for i, v := range f.strs {
+			b = appendLogfmtIndexKey(b, key, i)
+			b = appendLogfmtValue(b, v)
+		}
+		return b
+	case KindInts:
+		for i, v := range f.ints {
+			b = appendLogfmtIndexKey(b, key, i)
+			b = strconv.AppendInt(b, int64(v), 10)
+		}
+		return b
+	}
+
+	return t.appendAny(b, key, f.Val)
+}
+
+// appendAny appends the given value with the given
+// key. Slices, arrays and maps are flattened
+// recursively.
+func (t *LogfmtWriter) appendAny(b []byte, key string, v any) []byte {
+	switch vt := v.(type) {
+	case nil:
+		return append(appendLogfmtKey(b, key), "nil"...)
+	case string:
+		return appendLogfmtValue(appendLogfmtKey(b, key), vt)
+	case []byte:
+		return appendLogfmtValue(appendLogfmtKey(b, key), vt)
+	case time.Time:
+		return t.appendTime(appendLogfmtKey(b, key), vt)
+	case time.Duration:
+		return append(appendLogfmtKey(b, key), vt.String()...)
+	case error:
+		return appendLogfmtValue(appendLogfmtKey(b, key), vt.Error())
+	case fmt.Stringer:
+		return appendLogfmtValue(appendLogfmtKey(b, key), vt.String())
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			b = t.appendAny(b, key+"."+strconv.Itoa(i), rv.Index(i).Interface())
+		}
+		return b
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			b = t.appendAny(b, key+"."+fmt.Sprint(k.Interface()), rv.MapIndex(k).Interface())
+		}
+		return b
+	}
+
+	return appendLogfmtValue(appendLogfmtKey(b, key), fmt.Sprint(v))
+}
+
+func (t *LogfmtWriter) appendTime(b []byte, v time.Time) []byte {
+	format := t.TimeFormat
+	if format == "" {
+		format = time.RFC3339
+	}
+	var tb [64]byte
+	return appendLogfmtValue(b, v.AppendFormat(tb[:0], format))
+}
+
+// appendLogfmtKey appends a separating space, if
+// required, and the key followed by an equal sign
+// to b. Characters which are not allowed in keys
+// are replaced by underscores.
+func appendLogfmtKey(b []byte, key string) []byte {
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		b = append(b, ' ')
+	}
+
+	if key == "" {
+		key = "_"
+	}
+
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			b = append(b, '_')
+		} else {
+			b = utf8.AppendRune(b, r)
+		}
+	}
+
+	return append(b, '=')
+}
+
+// appendLogfmtIndexKey appends the key like
+// appendLogfmtKey with the given index attached.
+func appendLogfmtIndexKey(b []byte, key string, i int) []byte {
+	b = appendLogfmtKey(b, key)
+	b = append(b[:len(b)-1], '.')
+	b = strconv.AppendInt(b, int64(i), 10)
+	return append(b, '=')
+}
+
+// appendLogfmtValue appends v to b and quotes
+// it if it is empty or contains spaces, equal
+// signs, quotes or non-printable characters.
+func appendLogfmtValue[S string | []byte](b []byte, v S) []byte {
+	if !needsLogfmtQuoting(v) {
+		return append(b, v...)
+	}
+	return strconv.AppendQuote(b, string(v))
+}
+
+func needsLogfmtQuoting[S string | []byte](v S) bool {
+	if len(v) == 0 {
+		return true
+	}
+	for i := 0; i < len(v); {
+		c := v[i]
+		if c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := decodeRune(v[i:])
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
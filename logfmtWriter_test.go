@@ -0,0 +1,64 @@
+package rogu
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogfmtWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLogfmtWriter(&buf)
+	w.TimeFormat = ""
+
+	NewLogger(w).
+		Error().
+		Tag("Web Server").
+		Errf(errors.New("invalid host"), "failed: %s").
+		Str("empty", "").
+		Str("quoted", `say "hi"`).
+		Int("n", 5).
+		Dur("took", 1500*time.Millisecond).
+		Field("bad key=", "v").
+		Field("params", []any{"foo", "bar", 123}).
+		Field("a_map", map[string]any{"a": 1, "bazz": []int{5, 6}}).
+		Msg("hello world")
+
+	exp := `level=error tag="Web Server" msg="hello world" error="failed: invalid host" ` +
+		`empty="" quoted="say \"hi\"" n=5 took=1.5s bad_key_=v ` +
+		`params.0=foo params.1=bar params.2=123 a_map.a=1 a_map.bazz.0=5 a_map.bazz.1=6` + "\n"
+
+	if buf.String() != exp {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", buf.String(), exp)
+	}
+}
+
+func TestLogfmtWriterCaller(t *testing.T) {
+	var buf bytes.Buffer
+	NewLogger(NewLogfmtWriter(&buf)).SetCaller(true).Info().Send()
+
+	if !strings.HasPrefix(buf.String(), "ts=") ||
+		!strings.Contains(buf.String(), " caller=logfmtWriter_test.go:") ||
+		!strings.HasSuffix(buf.String(), ` msg=""`+"\n") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestLogfmtWriterErrs(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLogfmtWriter(&buf)
+	w.TimeFormat = ""
+
+	NewLogger(w).
+		Error().
+		Errs(errors.New("a"), errors.New("b")).
+		NamedErr("db", errors.New("db down")).
+		Msg("failed")
+
+	exp := `level=error msg=failed error=a error.1=b error.db="db down"` + "\n"
+	if buf.String() != exp {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", buf.String(), exp)
+	}
+}
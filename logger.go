@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"os"
 	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/zekrotja/rogu/level"
 )
@@ -16,19 +18,30 @@ type Logger interface {
 
 	slog.Handler
 
+	AddContextExtractor(ex ContextExtractor) Logger
+	AddHook(h Hook) Logger
 	AddWriter(w Writer) Logger
 	Copy() *logger
 	Debug() *Event
 	Error() *Event
 	Fatal() *Event
 	Info() *Event
+	Level() level.Level
 	Panic() *Event
 	SetCaller(enable bool) Logger
+	SetExitFunc(fn func(code int)) Logger
 	SetLevel(lvl level.Level) Logger
+	SetPanicFunc(fn func(msg string)) Logger
+	SetStackLevel(lvl level.Level) Logger
+	SetTagLevel(pattern string, lvl level.Level) Logger
+	SetTagLevels(levels map[string]level.Level) Logger
 	SetWriter(w Writer) Logger
+	TagLevels() map[string]level.Level
 	Tagged(tag string) Logger
 	Trace() *Event
+	UnsetTagLevel(pattern string) Logger
 	Warn() *Event
+	With(kv ...any) Logger
 	WithLevel(lvl level.Level) *Event
 }
 
@@ -37,9 +50,25 @@ type eventWriter interface {
 }
 
 type logger struct {
-	w      Writer
-	lvl    level.Level
-	caller bool
+	// cfg is never modified after it has been stored.
+	// Changes create a modified copy which replaces
+	// the current config atomically, so the logger can
+	// be reconfigured while events are written
+	// concurrently.
+	cfg    atomic.Pointer[loggerConfig]
+	cfgMtx sync.Mutex
+}
+
+type loggerConfig struct {
+	w             Writer
+	lvl           level.Level
+	tags          *tagLevels
+	caller        bool
+	stackLvl      level.Level
+	ctxExtractors []ContextExtractor
+	hooks         []Hook
+	exitFunc      func(code int)
+	panicFunc     func(msg string)
 }
 
 var _ Logger = (*logger)(nil)
@@ -50,15 +79,16 @@ var _ Logger = (*logger)(nil)
 // If no writer is specified or set via `SetWriter`,
 // the logger will never output anything.
 func NewLogger(writer ...Writer) Logger {
-	l := &logger{}
+	c := &loggerConfig{lvl: level.Info, stackLvl: level.Fatal}
 
 	if len(writer) == 1 {
-		l.w = writer[0]
+		c.w = writer[0]
 	} else {
-		l.w = MultiWriter(writer)
+		c.w = MultiWriter(writer)
 	}
 
-	l.SetLevel(level.Info)
+	l := &logger{}
+	l.cfg.Store(c)
 
 	return l
 }
@@ -66,40 +96,201 @@ func NewLogger(writer ...Writer) Logger {
 // SetWriter sets the specified writer to
 // the logger.
 func (t *logger) SetWriter(w Writer) Logger {
-	t.w = w
+	t.update(func(c *loggerConfig) {
+		c.w = w
+	})
 	return t
 }
 
 // AddWriter adds another writer to the logger.
 func (t *logger) AddWriter(w Writer) Logger {
-	if t.w == nil {
-		t.w = w
-	} else if mw, ok := t.w.(MultiWriter); ok {
-		t.w = MultiWriter(append(mw, w))
-	} else {
-		t.w = MultiWriter{t.w, w}
-	}
+	t.update(func(c *loggerConfig) {
+		if c.w == nil {
+			c.w = w
+		} else if mw, ok := c.w.(MultiWriter); ok {
+			// Copy the writers, so the MultiWriter of
+			// the previous config is not modified.
+			c.w = append(mw[:len(mw):len(mw)], w)
+		} else {
+			c.w = MultiWriter{c.w, w}
+		}
+	})
+	return t
+}
+
+// AddContextExtractor registers a ContextExtractor
+// which is applied to the context of every event
+// passed via `Event.Ctx`. The extracted fields are
+// added to the event when it is commited.
+func (t *logger) AddContextExtractor(ex ContextExtractor) Logger {
+	t.update(func(c *loggerConfig) {
+		c.ctxExtractors = append(c.ctxExtractors[:len(c.ctxExtractors):len(c.ctxExtractors)], ex)
+	})
+	return t
+}
+
+// AddHook registers a Hook which is applied to
+// every commited event before it is passed to the
+// writer. Hooks are applied in the order they have
+// been added and also apply to events of loggers
+// created via `Tagged` and `With`.
+func (t *logger) AddHook(h Hook) Logger {
+	t.update(func(c *loggerConfig) {
+		c.hooks = append(c.hooks[:len(c.hooks):len(c.hooks)], h)
+	})
 	return t
 }
 
 // SetLevel sets the minum log leven which
 // will be written.
 func (t *logger) SetLevel(lvl level.Level) Logger {
-	t.lvl = lvl
+	t.update(func(c *loggerConfig) {
+		c.lvl = lvl
+	})
+	return t
+}
+
+// Level returns the minimum log level set
+// via `SetLevel`.
+func (t *logger) Level() level.Level {
+	return t.config().lvl
+}
+
+// SetTagLevel sets the minimum log level for
+// events with a tag matching the given pattern.
+// It takes precedence over the level set via
+// `SetLevel`.
+//
+// Tags are matched hierarchically by their dot
+// separated segments, so the pattern `http`
+// matches the tags `http` and `http.router`.
+// Segments of the pattern may contain wildcards
+// like `*.db`. When multiple patterns match a
+// tag, the most specific one is used.
+//
+// The level is checked against the final tag of
+// the event when it is commited, so it also
+// applies to tagged loggers created before and
+// tags set via `Event.Tag`.
+//
+// Example:
+//
+//	l.SetLevel(level.Info).
+//	    SetTagLevel("Database", level.Trace).
+//	    SetTagLevel("http.*", level.Warn)
+func (t *logger) SetTagLevel(pattern string, lvl level.Level) Logger {
+	t.update(func(c *loggerConfig) {
+		c.tags = c.tags.with(pattern, lvl)
+	})
+	return t
+}
+
+// UnsetTagLevel removes the level set for the
+// given tag pattern via `SetTagLevel`.
+func (t *logger) UnsetTagLevel(pattern string) Logger {
+	t.update(func(c *loggerConfig) {
+		c.tags = c.tags.without(pattern)
+	})
 	return t
 }
 
+// SetTagLevels replaces all levels set per tag
+// pattern with the given levels.
+func (t *logger) SetTagLevels(levels map[string]level.Level) Logger {
+	tl := make(map[string]level.Level, len(levels))
+	for p, lvl := range levels {
+		tl[p] = lvl
+	}
+	t.update(func(c *loggerConfig) {
+		c.tags = newTagLevels(tl)
+	})
+	return t
+}
+
+// setLevels replaces the tag levels like
+// `SetTagLevels` and, if setLvl is true, sets the
+// level like `SetLevel` at once, so that no event
+// is checked against only one of the changes.
+func (t *logger) setLevels(tags map[string]level.Level, lvl level.Level, setLvl bool) {
+	tl := make(map[string]level.Level, len(tags))
+	for p, l := range tags {
+		tl[p] = l
+	}
+	t.update(func(c *loggerConfig) {
+		c.tags = newTagLevels(tl)
+		if setLvl {
+			c.lvl = lvl
+		}
+	})
+}
+
+// TagLevels returns a copy of the levels set
+// per tag pattern via `SetTagLevel`.
+func (t *logger) TagLevels() map[string]level.Level {
+	levels := make(map[string]level.Level)
+	for p, lvl := range t.config().tags.levelMap() {
+		levels[p] = lvl
+	}
+	return levels
+}
+
 // SetCaller enabled or disables attaching the
 // caller file and line to the event.
 func (t *logger) SetCaller(enable bool) Logger {
-	t.caller = enable
+	t.update(func(c *loggerConfig) {
+		c.caller = enable
+	})
+	return t
+}
+
+// SetStackLevel sets the least severe level of
+// events which carry a stack trace without
+// calling `Event.Stack`. Defaults to level.Fatal,
+// so panic and fatal events carry a stack trace.
+// Set it to level.Off to disable automatic stack
+// traces.
+//
+// When the error of an event carries a stack
+// trace via a `StackTrace()` method like errors
+// created with github.com/pkg/errors, that stack
+// trace is used instead of the current one.
+func (t *logger) SetStackLevel(lvl level.Level) Logger {
+	t.update(func(c *loggerConfig) {
+		c.stackLvl = lvl
+	})
+	return t
+}
+
+// SetExitFunc sets the function which is called
+// with the exit code after a fatal event has been
+// commited and the writers have been closed.
+// Defaults to os.Exit.
+//
+// This is useful to intercept fatal events in
+// tests.
+func (t *logger) SetExitFunc(fn func(code int)) Logger {
+	t.update(func(c *loggerConfig) {
+		c.exitFunc = fn
+	})
+	return t
+}
+
+// SetPanicFunc sets the function which is called
+// with the message after a panic event has been
+// commited. Defaults to a function which panics
+// with the message.
+func (t *logger) SetPanicFunc(fn func(msg string)) Logger {
+	t.update(func(c *loggerConfig) {
+		c.panicFunc = fn
+	})
 	return t
 }
 
 // Copy creates and returns a copy of the Logger.
 func (t *logger) Copy() *logger {
-	n := *t
-	return &n
+	n := &logger{}
+	n.cfg.Store(t.config())
+	return n
 }
 
 // Tagged returns a new logger which references
@@ -108,18 +299,34 @@ func (t *logger) Copy() *logger {
 // to the underlying logger will be projected
 // to the created logger.
 func (t *logger) Tagged(tag string) Logger {
-	n := &taggedLogger{
+	return &taggedLogger{
 		logger: t,
 		tag:    tag,
 	}
-	n.tag = tag
-	return n
+}
+
+// With returns a new logger which references
+// the origin logger but adds the given fields
+// to every created Entry. The passed values are
+// interpreted as alternating keys and values like
+// in `Event.Fields`. Changes made to the underlying
+// logger will be projected to the created logger.
+//
+// Example:
+//
+//	reqLogger := l.With("request_id", id, "user", userId)
+//	reqLogger.Info().Msg("Handling request")
+func (t *logger) With(kv ...any) Logger {
+	return &taggedLogger{
+		logger: t,
+		fields: appendFields(nil, kv),
+	}
 }
 
 // Close closes the set writers or all writers that
 // are added to the logger and which are closable.
 func (t *logger) Close() error {
-	if closer, ok := t.w.(Closer); ok {
+	if closer, ok := t.config().w.(Closer); ok {
 		return closer.Close()
 	}
 	return nil
@@ -152,8 +359,10 @@ func (t *logger) Error() *Event {
 
 // Trace creates a new log Event with level fatal.
 //
-// When commited, the programm will exit with exit
-// code 1.
+// When commited, all writers are closed and the
+// programm will exit with exit code 1 or the code
+// set via `Event.ExitCode`, even if the level of
+// the event is not written. See `SetExitFunc`.
 func (t *logger) Fatal() *Event {
 	return t.newEvent(level.Fatal)
 }
@@ -161,7 +370,8 @@ func (t *logger) Fatal() *Event {
 // Trace creates a new log Event with level panic.
 //
 // When commited, the program will panic at the
-// called point.
+// called point, even if the level of the event is
+// not written. See `SetPanicFunc`.
 func (t *logger) Panic() *Event {
 	return t.newEvent(level.Panic)
 }
@@ -173,24 +383,78 @@ func (t *logger) WithLevel(lvl level.Level) *Event {
 
 func (t *logger) newEvent(lvl level.Level) *Event {
 	e := newEvent(t, lvl)
-	if t.caller {
+	if t.config().caller {
 		e.Caller()
 	}
 	return e
 }
 
+// levelFor returns the minimum level of
+// events with the given tag.
+func (c *loggerConfig) levelFor(tag string) level.Level {
+	if lvl, ok := c.tags.lookup(tag); ok {
+		return lvl
+	}
+	return c.lvl
+}
+
+// config returns the current config snapshot
+// of the logger.
+func (t *logger) config() *loggerConfig {
+	if c := t.cfg.Load(); c != nil {
+		return c
+	}
+	return &loggerConfig{}
+}
+
+// update stores a modified copy of the
+// current config.
+func (t *logger) update(modify func(c *loggerConfig)) {
+	t.cfgMtx.Lock()
+	defer t.cfgMtx.Unlock()
+
+	c := *t.config()
+	modify(&c)
+	t.cfg.Store(&c)
+}
+
+// exit closes the writer and calls the exit
+// function with the given code.
+func (c *loggerConfig) exit(code int) {
+	if closer, ok := c.w.(Closer); ok {
+		closer.Close()
+	}
+	if c.exitFunc != nil {
+		c.exitFunc(code)
+	} else {
+		os.Exit(code)
+	}
+}
+
+// panic calls the panic function with the
+// given message.
+func (c *loggerConfig) panic(msg string) {
+	if c.panicFunc != nil {
+		c.panicFunc(msg)
+	} else {
+		panic(msg)
+	}
+}
+
 func (t *logger) write(e *Event, msg string) error {
+	c := t.config()
+
 	if e.lvl == level.Fatal {
-		defer os.Exit(1)
+		defer c.exit(e.exitCode)
 	} else if e.lvl == level.Panic {
-		defer panic(msg)
+		defer c.panic(msg)
 	}
 
-	if e.lvl > t.lvl {
+	if e.lvl > c.levelFor(e.tag) {
 		return nil
 	}
 
-	if t.w == nil {
+	if c.w == nil {
 		return nil
 	}
 
@@ -199,17 +463,94 @@ func (t *logger) write(e *Event, msg string) error {
 		line int
 	)
 	if e.caller {
-		_, file, line, _ = runtime.Caller(2)
-	}
-
-	return t.w.Write(
-		e.lvl,
-		e.fields,
-		e.tag,
-		e.err,
-		e.errFormat,
-		file,
-		line,
-		msg,
-	)
+		if e.callerFile != "" {
+			file, line = e.callerFile, e.callerLine
+		} else {
+			_, file, line, _ = runtime.Caller(3)
+		}
+	}
+
+	var stack []Frame
+	if e.stack || e.lvl <= c.stackLvl {
+		if stack = errorStack(e.err); stack == nil {
+			stack = callers(3)
+		}
+		for i := range e.errs {
+			e.errs[i].Stack = errorStack(e.errs[i].Err)
+		}
+	}
+
+	// Fields added when writing are not added to the
+	// event itself, so that they are not added twice
+	// when a failed write is retried.
+	fields := e.fields[:len(e.fields):len(e.fields)]
+
+	if e.err != nil {
+		fields = appendKV(fields, errorFields(e.err))
+	}
+	for _, ne := range e.errs {
+		fields = appendKV(fields, errorFields(ne.Err))
+	}
+
+	if e.ctx != nil {
+		for _, ex := range c.ctxExtractors {
+			fields = appendKV(fields, ex(e.ctx))
+		}
+	}
+
+	entry := Entry{
+		Time:       e.time,
+		Level:      e.lvl,
+		Tag:        e.tag,
+		Message:    msg,
+		Fields:     fields,
+		Err:        e.err,
+		ErrFormat:  e.errFormat,
+		Errs:       e.errs,
+		CallerFile: file,
+		CallerLine: line,
+		Stack:      stack,
+	}
+
+	if len(c.hooks) > 0 {
+		var ok bool
+		if entry, ok = runHooks(c.hooks, entry); !ok {
+			return nil
+		}
+	}
+
+	return c.w.Write(entry)
+}
+
+// appendKV appends the passed values alternating
+// as keys and values to fields like `Event.Fields`.
+func appendKV(fields []*Field, kv []any) []*Field {
+	for i := 0; i < len(kv); i += 2 {
+		f := &Field{Key: kv[i]}
+		if i+1 < len(kv) {
+			f.Val = kv[i+1]
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// runHooks applies the hooks to a copy of the
+// entry and returns false if any hook dropped it.
+//
+// It is kept out of write, so the entry only
+// escapes to the heap when hooks are registered.
+//
+//go:noinline
+func runHooks(hooks []Hook, entry Entry) (Entry, bool) {
+	// Hooks may modify the fields slice, which
+	// must stay untouched to give back the
+	// fields of the event to the pool.
+	entry.Fields = append([]*Field(nil), entry.Fields...)
+	for _, h := range hooks {
+		if !h.Run(&entry) {
+			return entry, false
+		}
+	}
+	return entry, true
 }
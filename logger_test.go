@@ -0,0 +1,173 @@
+package rogu
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestLoggerConcurrentReconfiguration(t *testing.T) {
+	var w1, w2 recordWriter
+
+	l := NewLogger(&w1)
+	loggers := []Logger{
+		l,
+		l.Tagged("Database"),
+		l.With("request_id", 123),
+		l.Tagged("http").With("user", "bob"),
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	ctx := context.Background()
+
+	for _, lg := range loggers {
+		wg.Add(1)
+		go func(lg Logger) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					lg.Info().Ctx(ctx).Str("k", "v").Msg("hello")
+					lg.Debug().Msg("hello")
+					lg.Enabled(ctx, 0)
+				}
+			}
+		}(lg)
+	}
+
+	for i := 0; i < 200; i++ {
+		l.SetLevel(level.Debug)
+		l.SetCaller(i%2 == 0)
+		l.SetTagLevel("Database", level.Trace)
+		l.AddWriter(&w2)
+		l.AddContextExtractor(func(ctx context.Context) []any {
+			return []any{"i", i}
+		})
+		l.SetWriter(MultiWriter{&w1})
+		l.SetLevel(level.Info)
+		_ = l.Copy()
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestLoggerAddWriterCopies(t *testing.T) {
+	var w1, w2, w3 recordWriter
+
+	mw := make(MultiWriter, 1, 10)
+	mw[0] = &w1
+
+	l1 := NewLogger(mw)
+	l2 := NewLogger(mw)
+	l1.AddWriter(&w2)
+	l2.AddWriter(&w3)
+
+	l1.Info().Msg("hello")
+
+	if len(w2.records) != 1 || len(w3.records) != 0 {
+		t.Errorf("writers of loggers sharing a MultiWriter are mixed up: %d %d",
+			len(w2.records), len(w3.records))
+	}
+}
+
+type closeRecordWriter struct {
+	entryWriter
+	closed bool
+}
+
+func (t *closeRecordWriter) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestLoggerFatal(t *testing.T) {
+	var (
+		w     closeRecordWriter
+		codes []int
+	)
+
+	l := NewLogger(&w).SetExitFunc(func(code int) {
+		if !w.closed {
+			t.Error("exit func called before the writer was closed")
+		}
+		codes = append(codes, code)
+	})
+
+	l.Fatal().Msg("fatal")
+	l.Tagged("tag").Fatal().ExitCode(3).Msg("fatal")
+	l.SetLevel(level.Off).Fatal().Msg("not written")
+	l.SetWriter(nil).Fatal().ExitCode(4).Msg("no writer")
+
+	if len(w.entries) != 2 || w.entries[0].Message != "fatal" || w.entries[1].Tag != "tag" {
+		t.Errorf("unexpected entries: %+v", w.entries)
+	}
+	if want := []int{1, 3, 1, 4}; !slices.Equal(codes, want) {
+		t.Errorf("exit codes are %v; want %v", codes, want)
+	}
+}
+
+func TestLoggerPanic(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "panic" {
+				t.Errorf("recovered %v; want panic", r)
+			}
+		}()
+		l.Panic().Msg("panic")
+		t.Error("logger did not panic")
+	}()
+
+	var msgs []string
+	l.SetPanicFunc(func(msg string) {
+		msgs = append(msgs, msg)
+	})
+
+	if err := l.Panic().Msg("intercepted"); err != nil {
+		t.Fatal(err)
+	}
+	l.SetLevel(level.Off).Panic().Msg("not written")
+
+	if len(w.entries) != 2 || w.entries[1].Message != "intercepted" {
+		t.Errorf("unexpected entries: %+v", w.entries)
+	}
+	if want := []string{"intercepted", "not written"}; !slices.Equal(msgs, want) {
+		t.Errorf("panic messages are %v; want %v", msgs, want)
+	}
+}
+
+func TestLoggerCaller(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w).SetCaller(true)
+
+	var lines []int
+	commit := []func(){
+		func() { _, _, line, _ := runtime.Caller(0); lines = append(lines, line); l.Info().Msg("msg") },
+		func() { _, _, line, _ := runtime.Caller(0); lines = append(lines, line); l.Info().Msgf("%s", "msgf") },
+		func() { _, _, line, _ := runtime.Caller(0); lines = append(lines, line); l.Info().Send() },
+	}
+	for _, c := range commit {
+		c()
+	}
+
+	if len(w.entries) != len(commit) {
+		t.Fatalf("expected %d entries, got %d", len(commit), len(w.entries))
+	}
+	for i, e := range w.entries {
+		if filepath.Base(e.CallerFile) != "logger_test.go" || e.CallerLine != lines[i] {
+			t.Errorf("entry %d: unexpected caller %s:%d; want logger_test.go:%d",
+				i, e.CallerFile, e.CallerLine, lines[i])
+		}
+	}
+}
@@ -1,9 +1,16 @@
 package rogu
 
-import "github.com/zekrotja/rogu/level"
-
 // MultiWriter writes events to
 // multiple registered writers.
+//
+// Using Route, writers can be added which only
+// receive entries matching the given rules.
+//
+// Example:
+//
+//	w := rogu.MultiWriter{}.
+//		Route(fileWriter, rogu.LevelFilter(level.Debug)).
+//		Route(alertWriter, rogu.LevelFilter(level.Error), rogu.ExcludeTagFilter("test"))
 type MultiWriter []Writer
 
 var (
@@ -11,18 +18,9 @@ var (
 	_ Closer = (MultiWriter)(nil)
 )
 
-func (t MultiWriter) Write(
-	lvl level.Level,
-	fields []*Field,
-	tag string,
-	lErr error,
-	lErrFormat string,
-	callerFile string,
-	callerLine int,
-	msg string,
-) (err error) {
+func (t MultiWriter) Write(e Entry) (err error) {
 	for _, w := range t {
-		if err = w.Write(lvl, fields, tag, lErr, lErrFormat, callerFile, callerLine, msg); err != nil {
+		if err = w.Write(e); err != nil {
 			return err
 		}
 	}
@@ -30,6 +28,13 @@ func (t MultiWriter) Write(
 	return nil
 }
 
+// Route returns a copy of the MultiWriter with
+// w added, which only receives entries matching
+// all of the given rules.
+func (t MultiWriter) Route(w Writer, rules ...FilterFunc) MultiWriter {
+	return append(t[:len(t):len(t)], NewFilterWriter(w, rules...))
+}
+
 // Close closes the set writers or all writers that
 // are added to the logger and which are closable.
 func (t MultiWriter) Close() error {
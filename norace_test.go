@@ -0,0 +1,5 @@
+//go:build !race
+
+package rogu
+
+const raceEnabled = false
@@ -0,0 +1,319 @@
+package otel
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/level"
+)
+
+// DefaultScope is the instrumentation scope name
+// of entries without tag.
+const DefaultScope = "github.com/zekrotja/rogu"
+
+// Encoder implements rogu.BatchEncoder and encodes
+// entries as OTLP log records in the JSON encoding
+// of the OTLP/HTTP protocol.
+//
+// The level is mapped to the SeverityNumber, the
+// tag to the instrumentation scope and the fields
+// to attributes. Fields added by TraceExtractor are
+// set as TraceId and SpanId of the log record. The
+// error and caller are added as `exception.message`,
+// `code.filepath` and `code.lineno` attributes.
+type Encoder struct {
+	// Resource holds the attributes of the resource
+	// producing the entries like `service.name`.
+	Resource map[string]any
+	// DefaultScope is the instrumentation scope name
+	// of entries without tag.
+	DefaultScope string
+}
+
+var _ rogu.BatchEncoder = (*Encoder)(nil)
+
+// NewEncoder returns a new Encoder with the given
+// service name as `service.name` resource attribute.
+func NewEncoder(serviceName string) *Encoder {
+	var t Encoder
+
+	t.Resource = map[string]any{"service.name": serviceName}
+	t.DefaultScope = DefaultScope
+
+	return &t
+}
+
+// NewWriter returns a new rogu.HTTPWriter which
+// exports entries via OTLP/HTTP to the given
+// endpoint like "http://localhost:4318/v1/logs".
+func NewWriter(endpoint, serviceName string) *rogu.HTTPWriter {
+	return rogu.NewHTTPWriter(endpoint, NewEncoder(serviceName))
+}
+
+func (t *Encoder) ContentType() string {
+	return "application/json"
+}
+
+func (t *Encoder) Encode(b []byte, entries []rogu.Entry) ([]byte, error) {
+	var scopes []*scopeLogs
+	for _, e := range entries {
+		name := e.Tag
+		if name == "" {
+			name = t.DefaultScope
+		}
+
+		var sl *scopeLogs
+		for _, s := range scopes {
+			if s.Scope.Name == name {
+				sl = s
+				break
+			}
+		}
+		if sl == nil {
+			sl = &scopeLogs{Scope: scope{Name: name}}
+			scopes = append(scopes, sl)
+		}
+
+		sl.LogRecords = append(sl.LogRecords, newLogRecord(e))
+	}
+
+	req := exportRequest{
+		ResourceLogs: []resourceLogs{{
+			Resource:  resource{Attributes: mapAttributes(t.Resource)},
+			ScopeLogs: scopes,
+		}},
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return b, err
+	}
+	return append(b, data...), nil
+}
+
+func newLogRecord(e rogu.Entry) logRecord {
+	now := uint64(time.Now().UnixNano())
+
+	r := logRecord{
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       severityNumber(e.Level),
+		SeverityText:         e.Level.String(),
+		Body:                 anyValue{StringValue: &e.Message},
+	}
+
+	if !e.Time.IsZero() {
+		r.TimeUnixNano = uint64(e.Time.UnixNano())
+	}
+
+	for _, f := range e.Fields {
+		if id, ok := f.Value().(string); ok {
+			switch {
+			case f.KeyString() == TraceIDKey && isHexID(id, 16):
+				r.TraceID = id
+				continue
+			case f.KeyString() == SpanIDKey && isHexID(id, 8):
+				r.SpanID = id
+				continue
+			}
+		}
+		r.Attributes = append(r.Attributes, keyValue{Key: f.KeyString(), Value: fieldValue(f)})
+	}
+
+	if e.Err != nil {
+		msg := e.ErrString()
+		r.Attributes = append(r.Attributes, keyValue{Key: "exception.message", Value: anyValue{StringValue: &msg}})
+	}
+
+	for _, ne := range e.Errs {
+		msg := ne.String()
+		r.Attributes = append(r.Attributes, keyValue{Key: "error." + ne.Name, Value: anyValue{StringValue: &msg}})
+	}
+
+	if e.CallerFile != "" {
+		file := e.CallerFile
+		line := int64(e.CallerLine)
+		r.Attributes = append(r.Attributes,
+			keyValue{Key: "code.filepath", Value: anyValue{StringValue: &file}},
+			keyValue{Key: "code.lineno", Value: anyValue{IntValue: &line}})
+	}
+
+	return r
+}
+
+// severityNumber maps the level to the
+// SeverityNumber of the OTel log data model.
+func severityNumber(lvl level.Level) int {
+	switch lvl {
+	case level.Trace:
+		return 1
+	case level.Debug:
+		return 5
+	case level.Info:
+		return 9
+	case level.Warn:
+		return 13
+	case level.Error:
+		return 17
+	case level.Fatal:
+		return 21
+	case level.Panic:
+		return 22
+	}
+	return 0
+}
+
+func isHexID(v string, size int) bool {
+	if len(v) != size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(v)
+	return err == nil
+}
+
+func fieldValue(f *rogu.Field) anyValue {
+	switch f.Kind() {
+	case rogu.KindString:
+		v := f.AsString()
+		return anyValue{StringValue: &v}
+	case rogu.KindInt64, rogu.KindDuration:
+		v := f.AsInt64()
+		return anyValue{IntValue: &v}
+	case rogu.KindUint64:
+		return uintValue(f.AsUint64())
+	case rogu.KindFloat64:
+		return floatValue(f.AsFloat64())
+	case rogu.KindBool:
+		v := f.AsBool()
+		return anyValue{BoolValue: &v}
+	case rogu.KindTime:
+		v := f.AsTime().Format(time.RFC3339Nano)
+		return anyValue{StringValue: &v}
+	case rogu.KindBytes:
+		return anyValue{BytesValue: f.AsBytes()}
+	}
+	return valueOf(f.Value())
+}
+
+func valueOf(v any) anyValue {
+	switch vt := v.(type) {
+	case nil:
+		return anyValue{}
+	case string:
+		return anyValue{StringValue: &vt}
+	case bool:
+		return anyValue{BoolValue: &vt}
+	case int:
+		return intValue(int64(vt))
+	case int8:
+		return intValue(int64(vt))
+	case int16:
+		return intValue(int64(vt))
+	case int32:
+		return intValue(int64(vt))
+	case int64:
+		return intValue(vt)
+	case uint:
+		return uintValue(uint64(vt))
+	case uint8:
+		return uintValue(uint64(vt))
+	case uint16:
+		return uintValue(uint64(vt))
+	case uint32:
+		return uintValue(uint64(vt))
+	case uint64:
+		return uintValue(vt)
+	case float32:
+		return floatValue(float64(vt))
+	case float64:
+		return floatValue(vt)
+	case time.Duration:
+		return intValue(int64(vt))
+	case time.Time:
+		s := vt.Format(time.RFC3339Nano)
+		return anyValue{StringValue: &s}
+	case []byte:
+		return anyValue{BytesValue: vt}
+	case error:
+		s := vt.Error()
+		return anyValue{StringValue: &s}
+	case fmt.Stringer:
+		s := vt.String()
+		return anyValue{StringValue: &s}
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return anyValue{}
+		}
+		return valueOf(rv.Elem().Interface())
+
+	case reflect.Slice, reflect.Array:
+		values := make([]anyValue, rv.Len())
+		for i := range values {
+			values[i] = valueOf(rv.Index(i).Interface())
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+
+	case reflect.Map:
+		values := make([]keyValue, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			values = append(values, keyValue{
+				Key:   fmt.Sprint(iter.Key().Interface()),
+				Value: valueOf(iter.Value().Interface()),
+			})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			return values[i].Key < values[j].Key
+		})
+		return anyValue{KvlistValue: &kvlistValue{Values: values}}
+	}
+
+	// Other values like structs are passed as
+	// their JSON representation.
+	s := fmt.Sprint(v)
+	if data, err := json.Marshal(v); err == nil {
+		s = string(data)
+	}
+	return anyValue{StringValue: &s}
+}
+
+func intValue(v int64) anyValue {
+	return anyValue{IntValue: &v}
+}
+
+func uintValue(v uint64) anyValue {
+	if v > math.MaxInt64 {
+		s := strconv.FormatUint(v, 10)
+		return anyValue{StringValue: &s}
+	}
+	return intValue(int64(v))
+}
+
+func floatValue(v float64) anyValue {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		return anyValue{StringValue: &s}
+	}
+	return anyValue{DoubleValue: &v}
+}
+
+func mapAttributes(m map[string]any) []keyValue {
+	attrs := make([]keyValue, 0, len(m))
+	for k, v := range m {
+		attrs = append(attrs, keyValue{Key: k, Value: valueOf(v)})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+	return attrs
+}
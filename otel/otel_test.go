@@ -0,0 +1,148 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/zekrotja/rogu"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var testSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+	SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+	TraceFlags: trace.FlagsSampled,
+})
+
+func TestTraceExtractor(t *testing.T) {
+	if kv := TraceExtractor(context.Background()); kv != nil {
+		t.Errorf("expected no fields, got %v", kv)
+	}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), testSpanContext)
+	kv := TraceExtractor(ctx)
+	if len(kv) != 4 ||
+		kv[1] != "0102030405060708090a0b0c0d0e0f10" ||
+		kv[3] != "0102030405060708" {
+		t.Errorf("unexpected fields: %v", kv)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var (
+		mtx    sync.Mutex
+		bodies [][]byte
+	)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mtx.Lock()
+		bodies = append(bodies, body)
+		mtx.Unlock()
+	}))
+	defer collector.Close()
+
+	w := NewWriter(collector.URL+"/v1/logs", "test-service")
+	w.OnError = func(err error) { t.Error(err) }
+
+	l := rogu.NewLogger(w)
+	l.AddContextExtractor(TraceExtractor)
+
+	ctx := trace.ContextWithSpanContext(context.Background(), testSpanContext)
+	l.Error().
+		Ctx(ctx).
+		Tag("Database").
+		Err(errors.New("connection refused")).
+		NamedErr("cache", errors.New("cache miss")).
+		Str("query", "SELECT 1").
+		Int("attempt", 3).
+		Strs("hosts", []string{"a", "b"}).
+		Msg("query failed")
+	l.Info().Msg("started")
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(bodies))
+	}
+
+	var req struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []map[string]any `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				Scope struct {
+					Name string `json:"name"`
+				} `json:"scope"`
+				LogRecords []struct {
+					TimeUnixNano   string           `json:"timeUnixNano"`
+					SeverityNumber int              `json:"severityNumber"`
+					SeverityText   string           `json:"severityText"`
+					Body           map[string]any   `json:"body"`
+					Attributes     []map[string]any `json:"attributes"`
+					TraceID        string           `json:"traceId"`
+					SpanID         string           `json:"spanId"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(bodies[0], &req); err != nil {
+		t.Fatal(err)
+	}
+
+	rl := req.ResourceLogs[0]
+	if v := rl.Resource.Attributes[0]; v["key"] != "service.name" {
+		t.Errorf("unexpected resource attributes: %v", rl.Resource.Attributes)
+	}
+
+	if len(rl.ScopeLogs) != 2 ||
+		rl.ScopeLogs[0].Scope.Name != "Database" ||
+		rl.ScopeLogs[1].Scope.Name != DefaultScope {
+		t.Fatalf("unexpected scopes: %+v", rl.ScopeLogs)
+	}
+
+	r := rl.ScopeLogs[0].LogRecords[0]
+	if r.SeverityNumber != 17 || r.SeverityText != "error" || r.Body["stringValue"] != "query failed" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if r.TimeUnixNano == "" {
+		t.Error("timeUnixNano is missing")
+	}
+	if r.TraceID != "0102030405060708090a0b0c0d0e0f10" || r.SpanID != "0102030405060708" {
+		t.Errorf("unexpected trace context: %s %s", r.TraceID, r.SpanID)
+	}
+
+	attrs := map[string]any{}
+	for _, a := range r.Attributes {
+		attrs[a["key"].(string)] = a["value"]
+	}
+	expected := map[string]string{
+		"query":             `{"stringValue":"SELECT 1"}`,
+		"attempt":           `{"intValue":"3"}`,
+		"hosts":             `{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}`,
+		"exception.message": `{"stringValue":"connection refused"}`,
+		"error.cache":       `{"stringValue":"cache miss"}`,
+	}
+	for k, v := range expected {
+		data, _ := json.Marshal(attrs[k])
+		if string(data) != v {
+			t.Errorf("%s: expected %s, got %s", k, v, data)
+		}
+	}
+	if _, ok := attrs[TraceIDKey]; ok {
+		t.Error("trace_id should not be an attribute")
+	}
+}
@@ -0,0 +1,39 @@
+// Package otel provides an OpenTelemetry bridge for
+// rogu: a ContextExtractor adding the trace context
+// of the active span to events and an Encoder to
+// export entries as OTLP log records via OTLP/HTTP.
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the field key of the trace ID
+	// added by TraceExtractor.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the field key of the span ID
+	// added by TraceExtractor.
+	SpanIDKey = "span_id"
+)
+
+// TraceExtractor is a rogu.ContextExtractor which
+// adds the trace ID and span ID of the span stored
+// in ctx as fields to the event.
+//
+// Example:
+//
+//	l.AddContextExtractor(otel.TraceExtractor)
+//	l.Info().Ctx(ctx).Msg("hello")
+func TraceExtractor(ctx context.Context) []any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []any{
+		TraceIDKey, sc.TraceID().String(),
+		SpanIDKey, sc.SpanID().String(),
+	}
+}
@@ -2,11 +2,14 @@ package rogu
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -66,6 +69,10 @@ type PrettyWriter struct {
 	StyleFieldErrorKey      lipgloss.Style
 	StyleFieldErrorValue    lipgloss.Style
 	StyleMessage            lipgloss.Style
+	StyleErrorTree          lipgloss.Style
+	StyleErrorCause         lipgloss.Style
+	StyleStackFunction      lipgloss.Style
+	StyleStackLocation      lipgloss.Style
 }
 
 var (
@@ -84,10 +91,18 @@ func NewPrettyWriter(outputs ...io.Writer) *PrettyWriter {
 	} else if len(outputs) == 1 {
 		t.Output = colorWriter(outputs[0])
 	} else {
-		for i := range outputs {
-			outputs[i] = colorWriter(outputs[i])
+		colored := make([]io.Writer, len(outputs))
+		var closers []io.Closer
+		for i, o := range outputs {
+			colored[i] = colorWriter(o)
+			if c, ok := closableOutput(o); ok {
+				closers = append(closers, c)
+			}
+		}
+		t.Output = &closingWriter{
+			Writer:  io.MultiWriter(colored...),
+			closers: closers,
 		}
-		t.Output = io.MultiWriter(outputs...)
 	}
 
 	t.TimeFormat = time.RFC3339
@@ -158,19 +173,22 @@ func NewPrettyWriter(outputs ...io.Writer) *PrettyWriter {
 	t.StyleMessage = lipgloss.NewStyle().
 		MarginRight(1)
 
+	t.StyleErrorTree = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("237"))
+	t.StyleErrorCause = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("160"))
+
+	t.StyleStackFunction = lipgloss.NewStyle().
+		MarginLeft(4).
+		MarginRight(1).
+		Foreground(lipgloss.Color("160"))
+	t.StyleStackLocation = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244"))
+
 	return &t
 }
 
-func (t *PrettyWriter) Write(
-	lvl level.Level,
-	fields []*Field,
-	tag string,
-	lErr error,
-	lErrFormat string,
-	callerFile string,
-	callerLine int,
-	msg string,
-) (err error) {
+func (t *PrettyWriter) Write(e Entry) (err error) {
 	buf := bufferPool.Get()
 	defer func() {
 		if buf.Cap() == bufferSize {
@@ -180,23 +198,22 @@ func (t *PrettyWriter) Write(
 
 	// -- Timestamp
 
-	if t.TimeFormat != "" {
-		now := time.Now().Format(t.TimeFormat)
-		if err = t.writeFormatted(buf, now, t.StyleTimestamp); err != nil {
+	if t.TimeFormat != "" && !e.Time.IsZero() {
+		if err = t.writeFormatted(buf, e.Time.Format(t.TimeFormat), t.StyleTimestamp); err != nil {
 			return err
 		}
 	}
 
 	// -- Level
 
-	if err = t.writeLvl(buf, lvl); err != nil {
+	if err = t.writeLvl(buf, e.Level); err != nil {
 		return err
 	}
 
 	// -- Caller
 
-	if callerFile != "" {
-		err = t.writeFormatted(buf, t.formatCaller(callerFile, callerLine), t.StyleCaller)
+	if e.CallerFile != "" {
+		err = t.writeFormatted(buf, t.formatCaller(e.CallerFile, e.CallerLine), t.StyleCaller)
 		if err != nil {
 			return err
 		}
@@ -204,30 +221,64 @@ func (t *PrettyWriter) Write(
 
 	// -- Tag
 
-	if tag != "" {
-		if err = t.writeFormatted(buf, capLen(tag, t.StyleTag.GetWidth()), t.StyleTag); err != nil {
+	if e.Tag != "" {
+		if err = t.writeFormatted(buf, capLen(e.Tag, t.StyleTag.GetWidth()), t.StyleTag); err != nil {
 			return err
 		}
 	}
 
 	// -- Message
 
-	if err = t.writeFormatted(buf, msg, t.StyleMessage); err != nil {
+	if err = t.writeFormatted(buf, e.Message, t.StyleMessage); err != nil {
 		return err
 	}
 
 	// -- Error
 
-	if lErr != nil {
-		t.writeErr(buf, lErr, lErrFormat)
+	if e.Err != nil {
+		if err = t.writeErr(buf, "error", e.Err, e.ErrFormat); err != nil {
+			return err
+		}
+	}
+
+	for _, ne := range e.Errs {
+		if err = t.writeErr(buf, "error."+ne.Name, ne.Err, ne.Format); err != nil {
+			return err
+		}
 	}
 
 	// -- Fields
 
-	if err = t.writeFields(buf, fields); err != nil {
+	if err = t.writeFields(buf, e.Fields); err != nil {
 		return err
 	}
 
+	// -- Error Tree
+
+	if e.Err != nil {
+		if err = t.writeErrTree(buf, "", e.Err); err != nil {
+			return err
+		}
+	}
+
+	for _, ne := range e.Errs {
+		if err = t.writeErrTree(buf, "error."+ne.Name, ne.Err); err != nil {
+			return err
+		}
+	}
+
+	// -- Stack
+
+	if err = t.writeStack(buf, "", e.Stack); err != nil {
+		return err
+	}
+
+	for _, ne := range e.Errs {
+		if err = t.writeStack(buf, "error."+ne.Name+" stack", ne.Stack); err != nil {
+			return err
+		}
+	}
+
 	// -- Finish
 
 	if err = t.writeString(buf, "\n"); err != nil {
@@ -251,7 +302,39 @@ func colorWriter(w io.Writer) io.Writer {
 	if w == os.Stderr || w == os.Stdout {
 		return colorable.NewColorable(w.(*os.File))
 	}
-	return colorable.NewNonColorable(w)
+	nc := colorable.NewNonColorable(w)
+	if c, ok := closableOutput(w); ok {
+		return &closingWriter{Writer: nc, closers: []io.Closer{c}}
+	}
+	return nc
+}
+
+// closableOutput returns w as io.Closer if it is
+// closable and not os.Stdout or os.Stderr.
+func closableOutput(w io.Writer) (io.Closer, bool) {
+	if w == os.Stderr || w == os.Stdout {
+		return nil, false
+	}
+	c, ok := w.(io.Closer)
+	return c, ok
+}
+
+// closingWriter wraps the output of a PrettyWriter
+// and closes the original outputs, which would not
+// be reachable through the wrapping writers.
+type closingWriter struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (t *closingWriter) Close() error {
+	var errs []error
+	for _, c := range t.closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (t *PrettyWriter) write(f io.Writer, p []byte) error {
@@ -297,54 +380,66 @@ func (t *PrettyWriter) writeLvl(f io.Writer, lvl level.Level) (err error) {
 }
 
 func (t *PrettyWriter) writeFields(f io.Writer, fields []*Field) (err error) {
+	var hasMultiple bool
+
 	for _, field := range fields {
-		if field.Val != nil {
-			field.valueKind = reflect.TypeOf(field.Val).Kind()
-			if field.valueKind == reflect.Slice || field.valueKind == reflect.Map {
-				continue
-			}
+		if shape := fieldShape(field); shape == reflect.Slice || shape == reflect.Map {
+			hasMultiple = true
+			continue
 		}
 
-		err = t.writeFormatted(f, fmt.Sprintf("%v=", field.Key), t.StyleFieldKey)
-		if err != nil {
+		if err = t.writeFormatted(f, field.KeyString()+"=", t.StyleFieldKey); err != nil {
 			return err
 		}
 
-		if err = t.writeFormatted(f, t.valueString(field.Val), t.StyleFieldValue); err != nil {
+		if err = t.writeFormatted(f, t.fieldString(field), t.StyleFieldValue); err != nil {
 			return err
 		}
 	}
 
+	if !hasMultiple {
+		return nil
+	}
+
 	for _, field := range fields {
-		if field.valueKind != reflect.Slice {
+		if fieldShape(field) != reflect.Slice {
 			continue
 		}
 
-		err = t.writeFormatted(f, fmt.Sprintf("%v=", field.Key), t.StyleFieldMultipleKey)
-		if err != nil {
+		if err = t.writeFormatted(f, field.KeyString()+"=", t.StyleFieldMultipleKey); err != nil {
 			return err
 		}
 
-		v := reflect.ValueOf(field.Val)
-		for i := 0; i < v.Len(); i++ {
-			vi := v.Index(i).Interface()
-			idx := fmt.Sprintf("% 2d ", i)
-			if err = t.writeFormatted(f, idx, t.StyleFieldMultipleIndex); err != nil {
-				return err
+		switch field.kind {
+		case KindStrings:
+			for i, v := range field.strs {
+				if err = t.writeMultipleValue(f, fmt.Sprintf("% 2d ", i), t.valueString(v)); err != nil {
+					return err
+				}
 			}
-			if err = t.writeFormatted(f, t.valueString(vi), t.StyleFieldMultipleValue); err != nil {
-				return err
+		case KindInts:
+			for i, v := range field.ints {
+				if err = t.writeMultipleValue(f, fmt.Sprintf("% 2d ", i), strconv.Itoa(v)); err != nil {
+					return err
+				}
+			}
+		default:
+			v := reflect.ValueOf(field.Val)
+			for i := 0; i < v.Len(); i++ {
+				vi := v.Index(i).Interface()
+				if err = t.writeMultipleValue(f, fmt.Sprintf("% 2d ", i), t.valueString(vi)); err != nil {
+					return err
+				}
 			}
 		}
 	}
 
 	for _, field := range fields {
-		if field.valueKind != reflect.Map {
+		if fieldShape(field) != reflect.Map {
 			continue
 		}
 
-		err = t.writeFormatted(f, fmt.Sprintf("%v=", field.Key), t.StyleFieldMultipleKey)
-		if err != nil {
+		if err = t.writeFormatted(f, field.KeyString()+"=", t.StyleFieldMultipleKey); err != nil {
 			return err
 		}
 
@@ -352,10 +447,7 @@ func (t *PrettyWriter) writeFields(f io.Writer, fields []*Field) (err error) {
 		for _, k := range v.MapKeys() {
 			vi := v.MapIndex(k).Interface()
 			idx := fmt.Sprintf("%v:", t.valueString(k.Interface()))
-			if err = t.writeFormatted(f, idx, t.StyleFieldMultipleIndex); err != nil {
-				return err
-			}
-			if err = t.writeFormatted(f, t.valueString(vi), t.StyleFieldMultipleValue); err != nil {
+			if err = t.writeMultipleValue(f, idx, t.valueString(vi)); err != nil {
 				return err
 			}
 		}
@@ -364,14 +456,178 @@ func (t *PrettyWriter) writeFields(f io.Writer, fields []*Field) (err error) {
 	return nil
 }
 
-func (t *PrettyWriter) writeErr(f io.Writer, lerr error, format string) (err error) {
-	if err = t.writeFormatted(f, "error=", t.StyleFieldErrorKey); err != nil {
+func (t *PrettyWriter) writeMultipleValue(f io.Writer, idx, v string) (err error) {
+	if err = t.writeFormatted(f, idx, t.StyleFieldMultipleIndex); err != nil {
+		return err
+	}
+	return t.writeFormatted(f, v, t.StyleFieldMultipleValue)
+}
+
+// fieldShape returns reflect.Slice or reflect.Map
+// if the fields value should be rendered as list
+// of multiple values.
+func fieldShape(f *Field) reflect.Kind {
+	switch f.kind {
+	case KindStrings, KindInts:
+		return reflect.Slice
+	case KindAny, KindObject:
+		if f.Val != nil {
+			return reflect.TypeOf(f.Val).Kind()
+		}
+	}
+	return reflect.Invalid
+}
+
+func (t *PrettyWriter) fieldString(f *Field) string {
+	switch f.kind {
+	case KindString:
+		return t.valueString(f.str)
+	case KindInt64:
+		return strconv.FormatInt(f.AsInt64(), 10)
+	case KindUint64:
+		return strconv.FormatUint(f.num, 10)
+	case KindFloat64:
+		return strconv.FormatFloat(f.AsFloat64(), 'g', -1, 64)
+	case KindBool:
+		return strconv.FormatBool(f.AsBool())
+	case KindDuration:
+		return f.AsDuration().String()
+	case KindTime:
+		return t.timeString(f.tm)
+	case KindBytes:
+		return t.valueString(string(f.byts))
+	}
+	return t.valueString(f.Val)
+}
+
+func (t *PrettyWriter) writeErr(f io.Writer, key string, lerr error, format string) (err error) {
+	if err = t.writeFormatted(f, key+"=", t.StyleFieldErrorKey); err != nil {
 		return err
 	}
 	if format == "" {
 		format = "%s"
 	}
-	return t.writeFormatted(f, fmt.Sprintf("\""+format+"\"", lerr), t.StyleFieldErrorValue)
+	// Messages of joined errors are separated by
+	// newlines, which would break the line of the
+	// entry. The causes are rendered as tree below.
+	msg := strings.ReplaceAll(fmt.Sprintf(format, lerr), "\n", "; ")
+	return t.writeFormatted(f, "\""+msg+"\"", t.StyleFieldErrorValue)
+}
+
+// writeErrTree writes the chain of errors wrapped
+// by lerr as tree below the entry. Each node only
+// shows the part of the message which is not
+// part of the messages of its causes. When key is
+// set, it is written as header above the tree.
+func (t *PrettyWriter) writeErrTree(f io.Writer, key string, lerr error) (err error) {
+	causes := unwrapErrors(lerr)
+	if len(causes) == 0 {
+		return nil
+	}
+
+	if err = t.writeHeader(f, key); err != nil {
+		return err
+	}
+
+	if msg := ownErrorMessage(lerr, causes); msg != "" {
+		if err = t.writeString(f, "\n    "); err != nil {
+			return err
+		}
+		if err = t.writeFormatted(f, msg, t.StyleErrorCause); err != nil {
+			return err
+		}
+	}
+
+	return t.writeErrCauses(f, causes, "", 0)
+}
+
+func (t *PrettyWriter) writeErrCauses(f io.Writer, causes []error, prefix string, depth int) (err error) {
+	causes = displayedCauses(causes, 0)
+
+	for i, c := range causes {
+		branch, indent := "├─ ", "│  "
+		if i == len(causes)-1 {
+			branch, indent = "└─ ", "   "
+		}
+
+		cc := unwrapErrors(c)
+		if depth >= maxErrorDepth {
+			cc = nil
+		}
+
+		if err = t.writeString(f, "\n    "); err != nil {
+			return err
+		}
+		if err = t.writeFormatted(f, prefix+branch, t.StyleErrorTree); err != nil {
+			return err
+		}
+		if err = t.writeFormatted(f, ownErrorMessage(c, cc), t.StyleErrorCause); err != nil {
+			return err
+		}
+
+		if len(cc) > 0 {
+			if err = t.writeErrCauses(f, cc, prefix+indent, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// displayedCauses replaces errors without an own
+// message, like joined errors, with their causes.
+func displayedCauses(causes []error, depth int) []error {
+	var res []error
+	for _, c := range causes {
+		if c == nil {
+			continue
+		}
+		cc := unwrapErrors(c)
+		if len(cc) > 0 && depth < maxErrorDepth && ownErrorMessage(c, cc) == "" {
+			res = append(res, displayedCauses(cc, depth+1)...)
+		} else {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// writeStack writes the frames of stack below
+// the entry. When key is set, it is written as
+// header above the frames.
+func (t *PrettyWriter) writeStack(f io.Writer, key string, stack []Frame) (err error) {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	if err = t.writeHeader(f, key); err != nil {
+		return err
+	}
+
+	for _, frame := range stack {
+		if err = t.writeString(f, "\n"); err != nil {
+			return err
+		}
+		if err = t.writeFormatted(f, frame.Function, t.StyleStackFunction); err != nil {
+			return err
+		}
+		loc := fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		if err = t.writeFormatted(f, loc, t.StyleStackLocation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *PrettyWriter) writeHeader(f io.Writer, key string) (err error) {
+	if key == "" {
+		return nil
+	}
+	if err = t.writeString(f, "\n"); err != nil {
+		return err
+	}
+	return t.writeFormatted(f, key+":", t.StyleFieldErrorKey)
 }
 
 func (t *PrettyWriter) valueString(v interface{}) string {
@@ -383,10 +639,7 @@ func (t *PrettyWriter) valueString(v interface{}) string {
 	case time.Duration:
 		return fmt.Sprintf("%s", vt)
 	case time.Time:
-		if t.TimeFormat != "" {
-			return t.valueString(vt.Format(t.TimeFormat))
-		}
-		return t.valueString(vt)
+		return t.timeString(vt)
 	case interface{ String() string }:
 		return fmt.Sprintf("\"%s\"", vt.String())
 	}
@@ -394,6 +647,16 @@ func (t *PrettyWriter) valueString(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
 
+// timeString returns tm formatted in TimeFormat
+// or, when TimeFormat is empty, in RFC 3339.
+func (t *PrettyWriter) timeString(tm time.Time) string {
+	format := t.TimeFormat
+	if format == "" {
+		format = time.RFC3339Nano
+	}
+	return "\"" + tm.Format(format) + "\""
+}
+
 func (t *PrettyWriter) formatCaller(file string, line int) string {
 	fname := fmt.Sprintf("%s:%d", filepath.Base(file), line)
 
@@ -1,10 +1,71 @@
 package rogu
 
 import (
+	"bytes"
+	"errors"
 	"io"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (t *closeBuffer) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestPrettyWriterClose(t *testing.T) {
+	f, err := NewRotatingFile(filepath.Join(t.TempDir(), "app.log"), RotateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogger(NewPrettyWriter(f))
+	l.Info().Msg("hello")
+	if err = l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Write([]byte("x")); !errors.Is(err, ErrWriterClosed) {
+		t.Errorf("file has not been closed: %v", err)
+	}
+
+	var a, b closeBuffer
+	var c bytes.Buffer
+	if err = NewPrettyWriter(&a, &b, &c).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !a.closed || !b.closed {
+		t.Error("outputs have not been closed")
+	}
+}
+
+func TestPrettyWriterTime(t *testing.T) {
+	tm := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	for _, format := range []string{"", time.DateOnly} {
+		var buf bytes.Buffer
+		w := NewPrettyWriter(&buf)
+		w.NoColor = true
+		w.TimeFormat = format
+
+		NewLogger(w).Info().Time("typed", tm).Field("any", tm).Msg("time")
+
+		want := tm.Format(time.RFC3339Nano)
+		if format != "" {
+			want = tm.Format(format)
+		}
+		if strings.Count(buf.String(), `"`+want+`"`) != 2 {
+			t.Errorf("format %q: missing times in output: %s", format, buf.String())
+		}
+	}
+}
+
 func BenchmarkPrettyWriter(b *testing.B) {
 	l := NewLogger()
 	l.SetWriter(NewPrettyWriter(io.Discard))
@@ -20,4 +81,10 @@ func BenchmarkPrettyWriter(b *testing.B) {
 			l.Info().Field("str", "str").Msg("bench")
 		}
 	})
+
+	b.Run("typed-fields", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			l.Info().Str("str", "str").Int("int", i).Msg("bench")
+		}
+	})
 }
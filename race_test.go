@@ -0,0 +1,8 @@
+//go:build race
+
+package rogu
+
+// raceEnabled is true when the tests are run
+// with the race detector, which randomly drops
+// pooled values and therefore causes allocations.
+const raceEnabled = true
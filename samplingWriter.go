@@ -0,0 +1,302 @@
+package rogu
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SamplingKeyFunc returns the key by which similar
+// entries are grouped for sampling.
+type SamplingKeyFunc func(e Entry) string
+
+// SampleByMessage groups entries by their level,
+// tag and message.
+func SampleByMessage(e Entry) string {
+	return e.Level.String() + "|" + e.Tag + "|" + e.Message
+}
+
+// SampleByCaller groups entries by their caller
+// file and line. To use this, caller recording
+// must be enabled on the logger.
+func SampleByCaller(e Entry) string {
+	return fmt.Sprintf("%s:%d", e.CallerFile, e.CallerLine)
+}
+
+type sampleCounter struct {
+	start      time.Time
+	n          uint64
+	suppressed uint64
+	entry      Entry
+}
+
+// SamplingWriter implements Writer and samples
+// and rate limits entries before passing them
+// to the wrapped Writer.
+//
+// Entries are grouped by the key returned by Key.
+// Within each Interval, the First entries of each
+// group are written. After that, only every
+// Thereafter-th entry is written.
+//
+// When Rate is set, a token bucket limits the
+// number of entries written per second to Rate
+// with bursts of up to Burst entries.
+//
+// For each group with suppressed entries, a
+// summary entry like "suppressed 123 similar
+// messages" is written once the interval of the
+// group has passed.
+type SamplingWriter struct {
+	// Interval is the duration of a sampling window.
+	// When set to 0, entries are not sampled but only
+	// rate limited and summaries are written every
+	// second.
+	Interval time.Duration
+	// First is the number of entries of each group
+	// which are written per interval.
+	First uint64
+	// Thereafter specifies that every n-th entry
+	// after the First entries is written. When set
+	// to 0, all entries after First are dropped.
+	Thereafter uint64
+	// Key returns the key by which entries are
+	// grouped.
+	Key SamplingKeyFunc
+	// Rate is the maximum number of entries per
+	// second. When set to 0, entries are not rate
+	// limited.
+	Rate float64
+	// Burst is the maximum number of entries which
+	// can be written at once when Rate is set.
+	Burst int
+
+	w Writer
+
+	mtx            sync.Mutex
+	counters       map[string]*sampleCounter
+	tokens         float64
+	lastRefill     time.Time
+	rateSuppressed uint64
+	rateEntry      Entry
+
+	startOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+	closed    bool
+}
+
+var (
+	_ Writer = (*SamplingWriter)(nil)
+	_ Closer = (*SamplingWriter)(nil)
+)
+
+// NewSamplingWriter returns a new SamplingWriter
+// wrapping w which writes the first 100 entries
+// and then every 100th entry with the same level,
+// tag and message per second.
+func NewSamplingWriter(w Writer) *SamplingWriter {
+	return &SamplingWriter{
+		Interval:   time.Second,
+		First:      100,
+		Thereafter: 100,
+		Key:        SampleByMessage,
+		w:          w,
+		counters:   make(map[string]*sampleCounter),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+func (t *SamplingWriter) Write(e Entry) error {
+	t.startOnce.Do(t.start)
+
+	now := time.Now()
+
+	t.mtx.Lock()
+
+	if t.closed {
+		t.mtx.Unlock()
+		return ErrWriterClosed
+	}
+
+	var (
+		summary *Entry
+		pass    = true
+		c       *sampleCounter
+	)
+
+	if t.Interval > 0 {
+		key := t.Key(e)
+		var ok bool
+		if c, ok = t.counters[key]; !ok {
+			c = &sampleCounter{start: now}
+			t.counters[key] = c
+		}
+
+		if now.Sub(c.start) >= t.Interval {
+			summary = c.summary()
+			c.start = now
+			c.n = 0
+		}
+
+		c.n++
+		pass = c.n <= t.First ||
+			(t.Thereafter > 0 && (c.n-t.First)%t.Thereafter == 0)
+	}
+
+	if pass && !t.takeToken(now) {
+		pass = false
+		t.rateSuppressed++
+		t.rateEntry = Entry{Level: e.Level, Tag: e.Tag}
+	} else if !pass {
+		c.suppressed++
+		c.entry = Entry{Level: e.Level, Tag: e.Tag, Message: e.Message}
+	}
+
+	t.mtx.Unlock()
+
+	if summary != nil {
+		if err := t.w.Write(*summary); err != nil {
+			return err
+		}
+	}
+
+	if !pass {
+		return nil
+	}
+
+	return t.w.Write(e)
+}
+
+// Close stops the summary goroutine, writes the
+// summaries of all suppressed entries and closes
+// the wrapped Writer, if it is closable.
+func (t *SamplingWriter) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mtx.Unlock()
+
+	t.startOnce.Do(func() { close(t.done) })
+	close(t.stop)
+	<-t.done
+
+	if err := t.writeSummaries(true); err != nil {
+		return err
+	}
+
+	if c, ok := t.w.(Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (t *SamplingWriter) start() {
+	go func() {
+		defer close(t.done)
+
+		interval := t.Interval
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.writeSummaries(false)
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+}
+
+// writeSummaries writes summaries for all groups
+// whose interval has passed and removes them. When
+// all is true, summaries for all groups are written.
+func (t *SamplingWriter) writeSummaries(all bool) error {
+	now := time.Now()
+
+	var summaries []Entry
+
+	t.mtx.Lock()
+	for key, c := range t.counters {
+		if !all && now.Sub(c.start) < t.Interval {
+			continue
+		}
+		if s := c.summary(); s != nil {
+			summaries = append(summaries, *s)
+		}
+		delete(t.counters, key)
+	}
+	if t.rateSuppressed > 0 {
+		e := t.rateEntry
+		e.Time = now
+		e.Message = fmt.Sprintf("suppressed %d messages due to rate limiting", t.rateSuppressed)
+		summaries = append(summaries, e)
+		t.rateSuppressed = 0
+	}
+	t.mtx.Unlock()
+
+	for _, s := range summaries {
+		if err := t.w.Write(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// takeToken refills the token bucket and takes
+// a token from it, if available. When no Rate is
+// set, true is always returned.
+func (t *SamplingWriter) takeToken(now time.Time) bool {
+	if t.Rate <= 0 {
+		return true
+	}
+
+	burst := float64(t.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	if t.lastRefill.IsZero() {
+		t.tokens = burst
+	} else {
+		t.tokens += now.Sub(t.lastRefill).Seconds() * t.Rate
+		if t.tokens > burst {
+			t.tokens = burst
+		}
+	}
+	t.lastRefill = now
+
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens--
+	return true
+}
+
+// summary returns an entry reporting the number of
+// suppressed entries and resets the counter or nil
+// if no entries have been suppressed.
+func (t *sampleCounter) summary() *Entry {
+	if t.suppressed == 0 {
+		return nil
+	}
+
+	e := t.entry
+	e.Time = time.Now()
+	e.Message = fmt.Sprintf("suppressed %d similar messages", t.suppressed)
+	e.Fields = []*Field{{Key: "sampled_message", Val: t.entry.Message}}
+	t.suppressed = 0
+
+	return &e
+}
@@ -0,0 +1,153 @@
+package rogu
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestSamplingWriter(t *testing.T) {
+	var w recordWriter
+	sw := NewSamplingWriter(&w)
+	sw.Interval = time.Hour
+	sw.First = 3
+	sw.Thereafter = 5
+
+	l := NewLogger(sw).SetLevel(level.All)
+	for i := 0; i < 20; i++ {
+		l.Debug().Msg("hot loop")
+	}
+	l.Info().Msg("other")
+
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 3 first + every 5th of the remaining 17 + other + summary
+	if len(w.records) != 3+3+1+1 {
+		t.Fatalf("unexpected number of records: %d", len(w.records))
+	}
+
+	summary := w.records[len(w.records)-1]
+	if summary["msg"] != "suppressed 14 similar messages" ||
+		summary["sampled_message"] != "hot loop" ||
+		summary["level"] != level.Debug {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+}
+
+func TestSamplingWriterRateLimit(t *testing.T) {
+	var w recordWriter
+	sw := NewSamplingWriter(&w)
+	sw.Key = SampleByCaller
+	sw.Rate = 1
+	sw.Burst = 2
+
+	l := NewLogger(sw)
+	for i := 0; i < 10; i++ {
+		l.Info().Msgf("msg %d", i)
+	}
+
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(w.records) != 3 {
+		t.Fatalf("unexpected number of records: %d", len(w.records))
+	}
+	if msg := w.records[2]["msg"]; msg != "suppressed 8 messages due to rate limiting" {
+		t.Errorf("unexpected summary: %v", msg)
+	}
+}
+
+func TestSamplingWriterZeroInterval(t *testing.T) {
+	var w recordWriter
+	sw := NewSamplingWriter(&w)
+	sw.Interval = 0
+	sw.Rate = 1
+	sw.Burst = 5
+
+	l := NewLogger(sw)
+	for i := 0; i < 10; i++ {
+		l.Info().Msg("same")
+	}
+
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 5 burst + rate limiting summary
+	if len(w.records) != 5+1 {
+		t.Fatalf("unexpected number of records: %d", len(w.records))
+	}
+	if msg := w.records[5]["msg"]; msg != "suppressed 5 messages due to rate limiting" {
+		t.Errorf("unexpected summary: %v", msg)
+	}
+}
+
+func TestSamplingWriterBurstBoundary(t *testing.T) {
+	tests := []struct {
+		burst   int
+		written int
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 3},
+	}
+
+	for _, tt := range tests {
+		var w recordWriter
+		sw := NewSamplingWriter(&w)
+		sw.Rate = 0.001
+		sw.Burst = tt.burst
+
+		l := NewLogger(sw)
+		for i := 0; i < tt.burst+2; i++ {
+			l.Info().Msgf("msg %d", i)
+		}
+
+		w.mtx.Lock()
+		written := len(w.records)
+		w.mtx.Unlock()
+		if written != tt.written {
+			t.Errorf("burst %d: %d entries written; want %d", tt.burst, written, tt.written)
+		}
+
+		sw.Close()
+	}
+}
+
+func TestSamplingWriterConcurrent(t *testing.T) {
+	var w recordWriter
+	sw := NewSamplingWriter(&w)
+	sw.Interval = time.Hour
+	sw.First = 10
+	sw.Thereafter = 0
+
+	l := NewLogger(sw)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Info().Msg("hot loop")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(w.records) != 10+1 {
+		t.Fatalf("unexpected number of records: %d", len(w.records))
+	}
+	if msg := w.records[10]["msg"]; msg != "suppressed 790 similar messages" {
+		t.Errorf("unexpected summary: %v", msg)
+	}
+}
@@ -3,69 +3,194 @@ package rogu
 import (
 	"context"
 	"log/slog"
+	"runtime"
 
 	"github.com/zekrotja/rogu/level"
 )
 
-const internalErrorKey = "__internal_error_key"
+const (
+	internalErrorKey = "__internal_error_key"
+	internalTagKey   = "__internal_tag_key"
+)
 
-var _ slog.Handler = (*logger)(nil)
-var _ slog.Handler = (*Event)(nil)
+var (
+	_ slog.Handler = (*logger)(nil)
+	_ slog.Handler = (*taggedLogger)(nil)
+	_ slog.Handler = (*SlogHandler)(nil)
+)
 
+// Enabled reports whether events with the given
+// level are written with any tag. The level set
+// for the tag of the record is checked when it is
+// handled.
 func (t *logger) Enabled(_ context.Context, lvl slog.Level) bool {
-	return toRoguLevel(lvl) <= t.lvl
+	c := t.config()
+	return toRoguLevel(lvl) <= c.tags.maxLevel(c.lvl)
 }
 
 func (t *logger) WithGroup(name string) slog.Handler {
-	return t.Tagged(name)
+	return NewSlogHandler(t).WithGroup(name)
 }
 
 func (t *logger) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return t.WithLevel(level.All).WithAttrs(attrs)
+	return NewSlogHandler(t).WithAttrs(attrs)
 }
 
 func (t *logger) Handle(ctx context.Context, rec slog.Record) error {
-	return t.WithLevel(toRoguLevel(rec.Level)).Handle(ctx, rec)
+	return NewSlogHandler(t).Handle(ctx, rec)
+}
+
+func (t *taggedLogger) WithGroup(name string) slog.Handler {
+	return NewSlogHandler(t).WithGroup(name)
+}
+
+func (t *taggedLogger) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewSlogHandler(t).WithAttrs(attrs)
 }
 
 func (t *taggedLogger) Handle(ctx context.Context, rec slog.Record) error {
-	return t.WithLevel(toRoguLevel(rec.Level)).Handle(ctx, rec)
+	return NewSlogHandler(t).Handle(ctx, rec)
 }
 
-func (t *Event) Enabled(_ context.Context, lvl slog.Level) bool {
-	return toRoguLevel(lvl) <= t.lvl
+// SlogHandler implements slog.Handler and
+// passes slog records as events to a Logger.
+//
+// Attributes in groups are added as fields with
+// their keys prefixed by the names of the
+// enclosing groups separated by dots (for example
+// `db.query.duration`).
+//
+// A SlogHandler is immutable, so it can safely be
+// used to handle many records from multiple
+// goroutines.
+type SlogHandler struct {
+	l      Logger
+	prefix string
+	bound  slogFields
 }
 
-func (t *Event) WithGroup(name string) slog.Handler {
-	return t.Tag(name)
+// NewSlogHandler returns a new SlogHandler which
+// passes records to the given Logger.
+func NewSlogHandler(l Logger) *SlogHandler {
+	return &SlogHandler{l: l}
 }
 
-func (t *Event) WithAttrs(attrs []slog.Attr) slog.Handler {
-	for _, a := range attrs {
-		if a.Key == internalErrorKey {
-			t.Err(a.Value.Any().(error))
-		} else {
-			t.Field(a.Key, a.Value.Any())
-		}
+func (t *SlogHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return t.l.Enabled(ctx, lvl)
+}
+
+func (t *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return t
 	}
-	return t
+	n := *t
+	n.prefix = t.prefix + name + "."
+	return &n
 }
 
-func (t *Event) Handle(_ context.Context, rec slog.Record) error {
-	t.lvl = toRoguLevel(rec.Level)
+func (t *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return t
+	}
+	n := *t
+	n.bound.kv = make([]any, 0, len(t.bound.kv)+len(attrs)*2)
+	n.bound.kv = append(n.bound.kv, t.bound.kv...)
+	n.bound.errs = t.bound.errs[:len(t.bound.errs):len(t.bound.errs)]
+	for _, a := range attrs {
+		n.bound.add(t.prefix, a)
+	}
+	return &n
+}
 
+func (t *SlogHandler) Handle(ctx context.Context, rec slog.Record) error {
+	rf := slogFields{
+		kv:  make([]any, 0, rec.NumAttrs()*2),
+		tag: t.bound.tag,
+		// The bound errors are shared between
+		// handlers, so they must not be appended to.
+		errs: t.bound.errs[:len(t.bound.errs):len(t.bound.errs)],
+	}
 	rec.Attrs(func(a slog.Attr) bool {
-		if a.Key == internalErrorKey {
-			t.Err(a.Value.Any().(error))
-		} else {
-			t.Field(a.Key, a.Value.Any())
-		}
+		rf.add(t.prefix, a)
 		return true
 	})
 
-	return t.Msg(rec.Message)
+	e := t.l.WithLevel(toRoguLevel(rec.Level)).
+		Ctx(ctx).
+		Fields(t.bound.kv...).
+		Fields(rf.kv...)
+
+	if rf.tag != "" {
+		e.Tag(rf.tag)
+	}
+
+	for _, ne := range rf.errs {
+		e.addErr(ne.Name, ne.Err, ne.Format)
+	}
+
+	e.time = rec.Time
+
+	if rec.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{rec.PC})
+		frame, _ := frames.Next()
+		e.callerFile = frame.File
+		e.callerLine = frame.Line
+	}
+
+	return e.Msg(rec.Message)
+}
+
+// slogFields collects resolved slog attributes
+// as alternating keys and values as well as the
+// tag and errors passed via TagAttr, ErrorAttr
+// and NamedErrorAttr.
+type slogFields struct {
+	kv   []any
+	tag  string
+	errs []NamedError
 }
 
+// add resolves the given attribute and appends it
+// with its key prefixed by prefix. Group values are
+// flattened recursively.
+func (t *slogFields) add(prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	switch a.Key {
+	case internalErrorKey:
+		switch v := a.Value.Any().(type) {
+		case error:
+			t.errs = append(t.errs, NamedError{Err: v})
+		case NamedError:
+			t.errs = append(t.errs, v)
+		}
+		return
+	case internalTagKey:
+		t.tag = a.Value.String()
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			t.add(prefix, ga)
+		}
+		return
+	}
+
+	t.kv = append(t.kv, prefix+a.Key, a.Value.Any())
+}
+
+// ErrorAttr returns a slog.Attr which sets the
+// given error as the error of the event. When
+// multiple errors are passed, they are added like
+// via `Event.Errs`.
 func ErrorAttr(err error) slog.Attr {
 	return slog.Attr{
 		Key:   internalErrorKey,
@@ -73,19 +198,39 @@ func ErrorAttr(err error) slog.Attr {
 	}
 }
 
+// NamedErrorAttr returns a slog.Attr which adds
+// the given error with the given name to the event
+// like `Event.NamedErr`.
+func NamedErrorAttr(name string, err error) slog.Attr {
+	return slog.Attr{
+		Key:   internalErrorKey,
+		Value: slog.AnyValue(NamedError{Name: name, Err: err}),
+	}
+}
+
+// TagAttr returns a slog.Attr which sets the
+// given tag as the tag of the event.
+//
+// Example:
+//
+//	dbLogger := slog.Default().With(rogu.TagAttr("Database"))
+func TagAttr(tag string) slog.Attr {
+	return slog.String(internalTagKey, tag)
+}
+
 // ---------------------------------------------------------------------
 
 func toRoguLevel(lvl slog.Level) level.Level {
-	switch lvl {
-	case slog.LevelDebug:
-		return level.Debug
-	case slog.LevelInfo:
-		return level.Info
-	case slog.LevelWarn:
-		return level.Warn
-	case slog.LevelError:
+	switch {
+	case lvl >= slog.LevelError:
 		return level.Error
+	case lvl >= slog.LevelWarn:
+		return level.Warn
+	case lvl >= slog.LevelInfo:
+		return level.Info
+	case lvl >= slog.LevelDebug:
+		return level.Debug
 	}
 
-	return level.Off
+	return level.Trace
 }
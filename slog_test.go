@@ -0,0 +1,146 @@
+package rogu
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type recordWriter struct {
+	mtx     sync.Mutex
+	records []map[string]any
+}
+
+func (t *recordWriter) Write(e Entry) error {
+	m := map[string]any{
+		slog.LevelKey:   e.Level,
+		slog.MessageKey: e.Message,
+	}
+	if !e.Time.IsZero() {
+		m[slog.TimeKey] = e.Time
+	}
+	if e.CallerFile != "" {
+		m[slog.SourceKey] = e.CallerFile
+	}
+	if e.Tag != "" {
+		m["tag"] = e.Tag
+	}
+	if e.Err != nil {
+		m["error"] = e.Err
+	}
+
+	for _, f := range e.Fields {
+		keys := strings.Split(f.KeyString(), ".")
+		group := m
+		for _, k := range keys[:len(keys)-1] {
+			g, ok := group[k].(map[string]any)
+			if !ok {
+				g = map[string]any{}
+				group[k] = g
+			}
+			group = g
+		}
+		group[keys[len(keys)-1]] = f.Value()
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.records = append(t.records, m)
+	return nil
+}
+
+func TestSlogHandler(t *testing.T) {
+	var w recordWriter
+	l := NewLogger(&w).SetLevel(level.All)
+
+	err := slogtest.TestHandler(NewSlogHandler(l), func() []map[string]any {
+		return w.records
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlogHandlerTagAndError(t *testing.T) {
+	var w recordWriter
+	l := NewLogger(&w).SetCaller(true)
+
+	sl := slog.New(l).With(TagAttr("Database"))
+	sl.Error("failed", ErrorAttr(errTest), slog.Group("db", slog.Int("attempt", 3)))
+
+	if len(w.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(w.records))
+	}
+
+	rec := w.records[0]
+	if rec["tag"] != "Database" {
+		t.Errorf("unexpected tag: %v", rec["tag"])
+	}
+	if rec["error"] != errTest {
+		t.Errorf("unexpected error: %v", rec["error"])
+	}
+	if db, _ := rec["db"].(map[string]any); db["attempt"] != int64(3) {
+		t.Errorf("unexpected group: %v", rec["db"])
+	}
+	if src, _ := rec[slog.SourceKey].(string); !strings.HasSuffix(src, "slog_test.go") {
+		t.Errorf("unexpected caller: %v", rec[slog.SourceKey])
+	}
+}
+
+func TestSlogHandlerMultipleErrors(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	errDB := errors.New("db")
+	sl := slog.New(NewSlogHandler(l)).With(NamedErrorAttr("db", errDB))
+	sl.Error("failed", ErrorAttr(errTest), ErrorAttr(errors.New("other")))
+	sl.Error("failed")
+
+	e := w.entries[0]
+	if e.Err != errTest {
+		t.Errorf("unexpected error: %v", e.Err)
+	}
+	if len(e.Errs) != 2 || e.Errs[0].Name != "db" || e.Errs[0].Err != errDB || e.Errs[1].Err.Error() != "other" {
+		t.Errorf("unexpected errors: %+v", e.Errs)
+	}
+
+	// The bound error must not be affected by the
+	// errors of the first record.
+	if e := w.entries[1]; e.Err != nil || len(e.Errs) != 1 || e.Errs[0].Name != "db" {
+		t.Errorf("unexpected errors of second record: %v %+v", e.Err, e.Errs)
+	}
+}
+
+func TestSlogHandlerConcurrent(t *testing.T) {
+	var w recordWriter
+	h := slog.New(NewLogger(&w)).With("foo", "bar").WithGroup("g")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			h.Info("msg", "i", i)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(w.records) != 20 {
+		t.Fatalf("expected 20 records, got %d", len(w.records))
+	}
+	for _, rec := range w.records {
+		if rec["foo"] != "bar" {
+			t.Errorf("bound field missing: %v", rec)
+		}
+		if g, _ := rec["g"].(map[string]any); g["i"] == nil {
+			t.Errorf("grouped field missing: %v", rec)
+		}
+	}
+}
+
+var errTest = errors.New("test error")
@@ -0,0 +1,135 @@
+package rogu
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+)
+
+// maxStackDepth is the maximum number of
+// frames captured for a stack trace.
+const maxStackDepth = 64
+
+// Frame is a single frame of a stack trace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+var (
+	frameType        = reflect.TypeOf(Frame{})
+	runtimeFrameType = reflect.TypeOf(runtime.Frame{})
+)
+
+// callers returns the stack of the calling
+// goroutine, skipping the given number of
+// frames above the caller of callers.
+func callers(skip int) []Frame {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return framesFromPCs(pcs[:n])
+}
+
+func framesFromPCs(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	stack := make([]Frame, 0, len(pcs))
+	for {
+		f, more := frames.Next()
+		stack = append(stack, Frame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+		})
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// errorStack returns the stack trace carried by
+// err or any error it wraps. When multiple errors
+// in the chain carry a stack trace, the one of the
+// innermost error is returned, because it is the
+// closest to where the error originated.
+//
+// An error carries a stack trace when it has a
+// method `StackTrace()` returning a slice of
+// program counters (like github.com/pkg/errors),
+// runtime.Frame or Frame.
+func errorStack(err error) (stack []Frame) {
+	for depth := 0; err != nil && depth <= maxErrorDepth; depth++ {
+		if s := stackTrace(err); s != nil {
+			stack = s
+		}
+
+		// Only the first error of joined errors is
+		// followed, because the stacks of the other
+		// errors are unrelated.
+		if causes := unwrapErrors(err); len(causes) > 0 {
+			err = causes[0]
+		} else {
+			err = nil
+		}
+	}
+	return stack
+}
+
+func stackTrace(err error) []Frame {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 ||
+		m.Type().Out(0).Kind() != reflect.Slice {
+		return nil
+	}
+
+	v := m.Call(nil)[0]
+	if v.Len() == 0 {
+		return nil
+	}
+
+	switch elem := v.Type().Elem(); {
+	case elem.Kind() == reflect.Uintptr:
+		pcs := make([]uintptr, v.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(v.Index(i).Uint())
+		}
+		return framesFromPCs(pcs)
+
+	case elem == frameType:
+		stack := make([]Frame, v.Len())
+		reflect.Copy(reflect.ValueOf(stack), v)
+		return stack
+
+	case elem == runtimeFrameType:
+		stack := make([]Frame, v.Len())
+		for i := range stack {
+			f := v.Index(i).Interface().(runtime.Frame)
+			stack[i] = Frame{Function: f.Function, File: f.File, Line: f.Line}
+		}
+		return stack
+	}
+
+	return nil
+}
+
+// appendStackString appends the stack formatted
+// like the stack traces of Go panics to b.
+func appendStackString(b []byte, stack []Frame) []byte {
+	for i, f := range stack {
+		if i > 0 {
+			b = append(b, '\n')
+		}
+		b = append(b, f.Function...)
+		b = append(b, "()\n\t"...)
+		b = append(b, f.File...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(f.Line), 10)
+	}
+	return b
+}
@@ -0,0 +1,166 @@
+package rogu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type entryWriter struct {
+	entries []Entry
+}
+
+func (t *entryWriter) Write(e Entry) error {
+	t.entries = append(t.entries, e.Clone())
+	return nil
+}
+
+type stackError struct {
+	pcs []uintptr
+}
+
+func newStackError() error {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(1, pcs[:])
+	return &stackError{pcs: pcs[:n]}
+}
+
+func (t *stackError) Error() string {
+	return "stack error"
+}
+
+func (t *stackError) StackTrace() []uintptr {
+	return t.pcs
+}
+
+func TestEventStack(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	l.Info().Msg("no stack")
+	l.Info().Stack().Msg("stack")
+
+	if len(w.entries[0].Stack) != 0 {
+		t.Errorf("unexpected stack: %v", w.entries[0].Stack)
+	}
+
+	stack := w.entries[1].Stack
+	if len(stack) == 0 {
+		t.Fatal("missing stack")
+	}
+	if !strings.HasSuffix(stack[0].Function, ".TestEventStack") {
+		t.Errorf("stack starts at %s; expected the caller", stack[0].Function)
+	}
+	if !strings.HasSuffix(stack[0].File, "stack_test.go") || stack[0].Line == 0 {
+		t.Errorf("invalid frame: %+v", stack[0])
+	}
+}
+
+func TestLoggerStackLevel(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	l.Error().Msg("error")
+	l.SetStackLevel(level.Error)
+	l.Error().Msg("error")
+	l.Tagged("tag").Warn().Msg("warn")
+
+	if len(w.entries[0].Stack) != 0 {
+		t.Error("error event carries stack with default stack level")
+	}
+	if len(w.entries[1].Stack) == 0 {
+		t.Error("error event carries no stack with stack level error")
+	}
+	if len(w.entries[2].Stack) != 0 {
+		t.Error("warn event carries stack with stack level error")
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	err := fmt.Errorf("wrapped: %w", newStackError())
+	l.Info().Err(err).Stack().Msg("error stack")
+
+	stack := w.entries[0].Stack
+	if len(stack) == 0 {
+		t.Fatal("missing stack")
+	}
+	if !strings.HasSuffix(stack[0].Function, ".newStackError") {
+		t.Errorf("stack starts at %s; expected the origin of the error", stack[0].Function)
+	}
+
+	if s := errorStack(errors.New("plain")); s != nil {
+		t.Errorf("unexpected stack of plain error: %v", s)
+	}
+}
+
+func TestJsonWriterStack(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(NewJsonWriter(&buf))
+
+	l.Info().Stack().Msg("stack")
+
+	var res struct {
+		Stack []struct {
+			Function string `json:"function"`
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+		} `json:"stack"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+	if len(res.Stack) == 0 || !strings.HasSuffix(res.Stack[0].Function, ".TestJsonWriterStack") ||
+		res.Stack[0].Line == 0 {
+		t.Errorf("unexpected stack: %+v", res.Stack)
+	}
+
+	buf.Reset()
+	w := NewJsonWriter(&buf)
+	w.Schema = JsonSchemaECS
+	l.SetWriter(w).Info().Stack().Msg("stack")
+
+	var ecs map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &ecs); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+	if s, _ := ecs["error.stack_trace"].(string); !strings.Contains(s, "TestJsonWriterStack()\n\t") {
+		t.Errorf("unexpected stack string: %q", s)
+	}
+}
+
+func TestNamedErrorStack(t *testing.T) {
+	var (
+		w   entryWriter
+		buf bytes.Buffer
+	)
+	l := NewLogger(MultiWriter{&w, NewJsonWriter(&buf)})
+
+	l.Error().NamedErr("db", newStackError()).Msg("no stack")
+	buf.Reset()
+	l.Error().NamedErr("db", newStackError()).Stack().Msg("stack")
+
+	if len(w.entries[0].Errs[0].Stack) != 0 {
+		t.Errorf("unexpected stack: %v", w.entries[0].Errs[0].Stack)
+	}
+	stack := w.entries[1].Errs[0].Stack
+	if len(stack) == 0 || !strings.HasSuffix(stack[0].Function, ".newStackError") {
+		t.Fatalf("unexpected stack of named error: %v", stack)
+	}
+
+	var res map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+	if s, _ := res["error.db.stack"].([]any); len(s) != len(stack) {
+		t.Errorf("unexpected stack of named error in output: %s", buf.String())
+	}
+}
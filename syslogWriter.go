@@ -0,0 +1,406 @@
+package rogu
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+// SyslogFormat specifies the message format
+// written by the SyslogWriter.
+type SyslogFormat int
+
+const (
+	// SyslogRFC5424 formats messages according to
+	// RFC 5424 with fields as structured data.
+	SyslogRFC5424 SyslogFormat = iota
+	// SyslogRFC3164 formats messages according to
+	// RFC 3164 (BSD syslog) with fields appended to
+	// the message in logfmt format.
+	SyslogRFC3164
+)
+
+// SyslogTagMode specifies where the tag of an
+// entry is put in the syslog message.
+type SyslogTagMode int
+
+const (
+	// SyslogTagAppName puts the tag into the
+	// APP-NAME. When the entry has no tag, the
+	// AppName of the writer is used.
+	SyslogTagAppName SyslogTagMode = iota
+	// SyslogTagMsgID puts the tag into the MSGID.
+	// This only applies to the RFC 5424 format.
+	SyslogTagMsgID
+)
+
+// Syslog facilities as specified in RFC 5424.
+const (
+	FacilityKern = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFtp
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// SyslogWriter implements Writer and sends entries
+// to a syslog server.
+//
+// Supported networks are "udp", "tcp", "tls",
+// "unix" and "unixgram". Messages sent over stream
+// connections ("tcp", "tls" and "unix") are framed
+// using octet counting as specified in RFC 6587.
+//
+// The connection is established on the first write.
+// When writing fails, the connection is
+// re-established and the write is retried once.
+type SyslogWriter struct {
+	mtx  sync.Mutex
+	conn net.Conn
+
+	Network   string
+	Address   string
+	TLSConfig *tls.Config
+
+	Format   SyslogFormat
+	TagMode  SyslogTagMode
+	Facility int
+	Hostname string
+	AppName  string
+	// StructuredDataID is the SD-ID of the structured
+	// data element containing the fields of the entry.
+	StructuredDataID string
+
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+var (
+	_ Writer = (*SyslogWriter)(nil)
+	_ Closer = (*SyslogWriter)(nil)
+)
+
+// NewSyslogWriter returns a new SyslogWriter
+// sending RFC 5424 formatted messages to the
+// given network and address.
+func NewSyslogWriter(network, address string) *SyslogWriter {
+	var t SyslogWriter
+
+	t.Network = network
+	t.Address = address
+	t.Facility = FacilityUser
+	t.Hostname, _ = os.Hostname()
+	t.AppName = filepath.Base(os.Args[0])
+	t.StructuredDataID = "fields@32473"
+	t.DialTimeout = 5 * time.Second
+	t.WriteTimeout = 5 * time.Second
+
+	return &t
+}
+
+func (t *SyslogWriter) Write(e Entry) (err error) {
+	buf := bufferPool.Get()
+	defer func() {
+		if buf.Cap() == bufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	b := buf.AvailableBuffer()
+	if t.Format == SyslogRFC3164 {
+		b = t.appendRFC3164(b, e)
+	} else {
+		b = t.appendRFC5424(b, e)
+	}
+	buf.Write(b)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if err = t.send(buf.Bytes()); err == nil {
+		return nil
+	}
+
+	// Retry once with a new connection.
+	t.closeConn()
+	return t.send(buf.Bytes())
+}
+
+// Close closes the connection to the
+// syslog server.
+func (t *SyslogWriter) Close() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.closeConn()
+}
+
+func (t *SyslogWriter) send(msg []byte) (err error) {
+	if t.conn == nil {
+		if t.conn, err = t.dial(); err != nil {
+			return err
+		}
+	}
+
+	if t.WriteTimeout > 0 {
+		t.conn.SetWriteDeadline(time.Now().Add(t.WriteTimeout))
+	}
+
+	if t.isStream() {
+		var lb [20]byte
+		frame := net.Buffers{
+			append(strconv.AppendInt(lb[:0], int64(len(msg)), 10), ' '),
+			msg,
+		}
+		_, err = frame.WriteTo(t.conn)
+		return err
+	}
+
+	_, err = t.conn.Write(msg)
+	return err
+}
+
+func (t *SyslogWriter) dial() (net.Conn, error) {
+	dialer := net.Dialer{Timeout: t.DialTimeout}
+	if t.Network == "tls" {
+		// The nil *tls.Conn returned on errors must
+		// not be returned as non-nil net.Conn.
+		conn, err := tls.DialWithDialer(&dialer, "tcp", t.Address, t.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	}
+	return dialer.Dial(t.Network, t.Address)
+}
+
+func (t *SyslogWriter) closeConn() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+func (t *SyslogWriter) isStream() bool {
+	switch t.Network {
+	case "tcp", "tcp4", "tcp6", "tls", "unix":
+		return true
+	}
+	return false
+}
+
+func (t *SyslogWriter) priority(lvl level.Level) int {
+	return t.Facility*8 + syslogSeverity(lvl)
+}
+
+func (t *SyslogWriter) appName(e Entry) string {
+	if t.TagMode == SyslogTagAppName && e.Tag != "" {
+		return e.Tag
+	}
+	return t.AppName
+}
+
+func (t *SyslogWriter) appendRFC5424(b []byte, e Entry) []byte {
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(t.priority(e.Level)), 10)
+	b = append(b, ">1 "...)
+
+	if e.Time.IsZero() {
+		b = append(b, '-')
+	} else {
+		b = e.Time.AppendFormat(b, syslogTimeFormat)
+	}
+
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, t.Hostname, 255)
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, t.appName(e), 48)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(os.Getpid()), 10)
+	b = append(b, ' ')
+	if t.TagMode == SyslogTagMsgID {
+		b = appendSyslogHeaderField(b, e.Tag, 32)
+	} else {
+		b = append(b, '-')
+	}
+	b = append(b, ' ')
+
+	if len(e.Fields) == 0 && e.Err == nil && len(e.Errs) == 0 && e.CallerFile == "" {
+		b = append(b, '-')
+	} else {
+		b = append(b, '[')
+		b = appendSyslogHeaderField(b, t.StructuredDataID, 32)
+		if e.Err != nil {
+			b = appendSyslogParam(b, "error", e.ErrString())
+		}
+		for _, ne := range e.Errs {
+			b = appendSyslogParam(b, "error."+ne.Name, ne.String())
+		}
+		if e.CallerFile != "" {
+			b = appendSyslogParam(b, "caller", e.CallerFile+":"+strconv.Itoa(e.CallerLine))
+		}
+		for _, f := range e.Fields {
+			b = appendSyslogParam(b, f.KeyString(), syslogFieldValue(f))
+		}
+		b = append(b, ']')
+	}
+
+	if e.Message != "" {
+		b = append(b, ' ')
+		b = append(b, e.Message...)
+	}
+
+	return b
+}
+
+func (t *SyslogWriter) appendRFC3164(b []byte, e Entry) []byte {
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(t.priority(e.Level)), 10)
+	b = append(b, '>')
+
+	ts := e.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	b = ts.AppendFormat(b, time.Stamp)
+
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, t.Hostname, 255)
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, t.appName(e), 32)
+	b = append(b, '[')
+	b = strconv.AppendInt(b, int64(os.Getpid()), 10)
+	b = append(b, "]: "...)
+	b = append(b, e.Message...)
+
+	if e.Err != nil {
+		b = appendLogfmtKey(b, "error")
+		b = appendLogfmtValue(b, e.ErrString())
+	}
+
+	for _, ne := range e.Errs {
+		b = appendLogfmtKey(b, "error."+ne.Name)
+		b = appendLogfmtValue(b, ne.String())
+	}
+
+	if e.CallerFile != "" {
+		b = appendLogfmtKey(b, "caller")
+		b = appendLogfmtValue(b, e.CallerFile+":"+strconv.Itoa(e.CallerLine))
+	}
+
+	lf := LogfmtWriter{TimeFormat: time.RFC3339}
+	for _, f := range e.Fields {
+		b = lf.appendField(b, f)
+	}
+
+	return b
+}
+
+// appendSyslogHeaderField appends v to b with
+// all characters which are not printable ASCII
+// replaced by underscores and capped to maxLen.
+// When v is empty, the NILVALUE "-" is appended.
+func appendSyslogHeaderField(b []byte, v string, maxLen int) []byte {
+	if v == "" {
+		return append(b, '-')
+	}
+	if len(v) > maxLen {
+		v = v[:maxLen]
+	}
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// appendSyslogParam appends a structured data
+// parameter with the given name and value to b.
+func appendSyslogParam(b []byte, name, value string) []byte {
+	b = append(b, ' ')
+
+	if name == "" {
+		name = "_"
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+
+	b = append(b, '=', '"')
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '"' || c == '\\' || c == ']' {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	return append(b, '"')
+}
+
+func syslogFieldValue(f *Field) string {
+	switch f.kind {
+	case KindString:
+		return f.str
+	case KindBytes:
+		return string(f.byts)
+	case KindTime:
+		return f.tm.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(f.Value())
+}
+
+func syslogSeverity(lvl level.Level) int {
+	switch lvl {
+	case level.Panic:
+		return 1 // alert
+	case level.Fatal:
+		return 2 // critical
+	case level.Error:
+		return 3 // error
+	case level.Warn:
+		return 4 // warning
+	case level.Info:
+		return 6 // informational
+	}
+	return 7 // debug
+}
@@ -0,0 +1,295 @@
+package rogu
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var rfc5424Pattern = regexp.MustCompile(
+	`^<11>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}\S+ host Database \d+ - ` +
+		`\[fields@32473 error="conn \\"refused\\"" query="SELECT \\] 1" n="3"\] query failed$`)
+
+func logSyslogTestEntry(t *testing.T, w *SyslogWriter) {
+	t.Helper()
+	err := NewLogger(w).
+		Error().
+		Tag("Database").
+		Err(errors.New(`conn "refused"`)).
+		Str("query", "SELECT ] 1").
+		Int("n", 3).
+		Msg("query failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	w := NewSyslogWriter("udp", pc.LocalAddr().String())
+	w.Hostname = "host"
+	defer w.Close()
+
+	logSyslogTestEntry(t, w)
+
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(buf[:n]); !rfc5424Pattern.MatchString(msg) {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+// serveSyslogStream accepts connections on ln and
+// passes the octet counted messages read from them
+// to msgs. When max is greater than 0, connections
+// are closed after max messages have been read.
+func serveSyslogStream(ln net.Listener, msgs chan<- string, max int) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for i := 0; max <= 0 || i < max; i++ {
+				lenStr, err := r.ReadString(' ')
+				if err != nil {
+					return
+				}
+				n, _ := strconv.Atoi(strings.TrimSpace(lenStr))
+				msg := make([]byte, n)
+				if _, err = io.ReadFull(r, msg); err != nil {
+					return
+				}
+				msgs <- string(msg)
+			}
+		}()
+	}
+}
+
+func TestSyslogWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 10)
+	go serveSyslogStream(ln, msgs, 0)
+
+	w := NewSyslogWriter("tcp", ln.Addr().String())
+	w.Hostname = "host"
+	defer w.Close()
+
+	logSyslogTestEntry(t, w)
+	// Closing the connection forces a reconnect
+	// on the next write.
+	w.Close()
+	logSyslogTestEntry(t, w)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-msgs:
+			if !rfc5424Pattern.MatchString(msg) {
+				t.Errorf("unexpected message: %s", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for message")
+		}
+	}
+}
+
+func TestSyslogWriterUnixgramRFC3164(t *testing.T) {
+	dir, err := os.MkdirTemp("", "rogu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr := filepath.Join(dir, "log.sock")
+	pc, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Skipf("unixgram not supported: %s", err)
+	}
+	defer pc.Close()
+
+	w := NewSyslogWriter("unixgram", addr)
+	w.Format = SyslogRFC3164
+	w.Hostname = "host"
+	w.Facility = FacilityLocal0
+	defer w.Close()
+
+	logSyslogTestEntry(t, w)
+
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := regexp.MustCompile(`^<131>\w{3} [ \d]\d \d\d:\d\d:\d\d host Database\[\d+\]: ` +
+		`query failed error="conn \\"refused\\"" query="SELECT \] 1" n=3$`)
+	if msg := string(buf[:n]); !pattern.MatchString(msg) {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+func TestSyslogWriterErrs(t *testing.T) {
+	var w entryWriter
+	NewLogger(&w).
+		Error().
+		Errs(errors.New("a"), errors.New("b")).
+		NamedErr("db", errors.New("db down")).
+		Msg("failed")
+	e := w.entries[0]
+
+	sw := NewSyslogWriter("udp", "127.0.0.1:514")
+	sw.Hostname = "host"
+
+	msg := string(sw.appendRFC5424(nil, e))
+	if !strings.HasSuffix(msg, `[fields@32473 error="a" error.1="b" error.db="db down"] failed`) {
+		t.Errorf("unexpected RFC 5424 message: %s", msg)
+	}
+
+	sw.Format = SyslogRFC3164
+	msg = string(sw.appendRFC3164(nil, e))
+	if !strings.HasSuffix(msg, `]: failed error=a error.1=b error.db="db down"`) {
+		t.Errorf("unexpected RFC 3164 message: %s", msg)
+	}
+}
+
+func TestSyslogWriterReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// The server drops each connection after the
+	// first message.
+	msgs := make(chan string, 100)
+	go serveSyslogStream(ln, msgs, 1)
+
+	w := NewSyslogWriter("tcp", ln.Addr().String())
+	w.Hostname = "host"
+	defer w.Close()
+
+	logSyslogTestEntry(t, w)
+	<-msgs
+
+	// Writes to the dropped connection may succeed
+	// until the peer has reset it, so the entry is
+	// written until it is received on a new one.
+	deadline := time.Now().Add(5 * time.Second)
+	for received := false; !received; {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for message on new connection")
+		}
+		logSyslogTestEntry(t, w)
+		select {
+		case msg := <-msgs:
+			if !rfc5424Pattern.MatchString(msg) {
+				t.Errorf("unexpected message: %s", msg)
+			}
+			received = true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestSyslogWriterTLS(t *testing.T) {
+	cert, pool := newTestCertificate(t)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 10)
+	go serveSyslogStream(ln, msgs, 0)
+
+	w := NewSyslogWriter("tls", ln.Addr().String())
+	w.Hostname = "host"
+	w.TLSConfig = &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+	defer w.Close()
+
+	logSyslogTestEntry(t, w)
+
+	select {
+	case msg := <-msgs:
+		if !rfc5424Pattern.MatchString(msg) {
+			t.Errorf("unexpected message: %s", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	// Writing fails without trusting the certificate
+	// of the server.
+	w2 := NewSyslogWriter("tls", ln.Addr().String())
+	defer w2.Close()
+	if err = w2.Write(Entry{Message: "untrusted"}); err == nil {
+		t.Error("expected error for untrusted certificate")
+	}
+}
+
+// newTestCertificate returns a self-signed
+// certificate for 127.0.0.1 and a pool trusting it.
+func newTestCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rogu test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
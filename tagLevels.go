@@ -0,0 +1,171 @@
+package rogu
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+// maxCachedTags limits the number of resolved tags
+// cached by a tagLevels instance, so that the cache
+// does not grow indefinitely with dynamic tags.
+const maxCachedTags = 1024
+
+// tagLevels holds levels set for tag patterns.
+//
+// A tagLevels instance is never modified after it
+// has been created. Setting or removing a tag level
+// creates a modified copy, so resolved levels can
+// be cached.
+type tagLevels struct {
+	levels   map[string]level.Level
+	patterns []tagPattern
+
+	cache     sync.Map
+	cacheSize atomic.Int64
+}
+
+type tagPattern struct {
+	pattern  string
+	segments []string
+	lvl      level.Level
+}
+
+func newTagLevels(levels map[string]level.Level) *tagLevels {
+	t := &tagLevels{levels: levels}
+
+	for p, lvl := range levels {
+		t.patterns = append(t.patterns, tagPattern{
+			pattern:  p,
+			segments: strings.Split(p, "."),
+			lvl:      lvl,
+		})
+	}
+
+	// Sort patterns from most to least specific, so
+	// the first matching pattern is the best match.
+	sort.Slice(t.patterns, func(i, j int) bool {
+		a, b := t.patterns[i], t.patterns[j]
+		if len(a.segments) != len(b.segments) {
+			return len(a.segments) > len(b.segments)
+		}
+		if wa, wb := wildcardSegments(a.segments), wildcardSegments(b.segments); wa != wb {
+			return wa < wb
+		}
+		return a.pattern < b.pattern
+	})
+
+	return t
+}
+
+// with returns a copy of t with the level of the
+// given pattern set to lvl.
+func (t *tagLevels) with(pattern string, lvl level.Level) *tagLevels {
+	levels := make(map[string]level.Level, len(t.levelMap())+1)
+	for p, l := range t.levelMap() {
+		levels[p] = l
+	}
+	levels[pattern] = lvl
+	return newTagLevels(levels)
+}
+
+// without returns a copy of t without the
+// given pattern.
+func (t *tagLevels) without(pattern string) *tagLevels {
+	levels := make(map[string]level.Level, len(t.levelMap()))
+	for p, l := range t.levelMap() {
+		if p != pattern {
+			levels[p] = l
+		}
+	}
+	return newTagLevels(levels)
+}
+
+func (t *tagLevels) levelMap() map[string]level.Level {
+	if t == nil {
+		return nil
+	}
+	return t.levels
+}
+
+// lookup returns the level of the most specific
+// pattern matching tag. ok is false if no pattern
+// matches.
+func (t *tagLevels) lookup(tag string) (lvl level.Level, ok bool) {
+	if t == nil || tag == "" || len(t.patterns) == 0 {
+		return 0, false
+	}
+
+	if v, cached := t.cache.Load(tag); cached {
+		r := v.(tagLookup)
+		return r.lvl, r.ok
+	}
+
+	segments := strings.Split(tag, ".")
+	for _, p := range t.patterns {
+		if matchTagPattern(p.segments, segments) {
+			lvl, ok = p.lvl, true
+			break
+		}
+	}
+
+	// The cache is cleared when it is full. The size
+	// is only approximate under concurrent lookups.
+	if t.cacheSize.Add(1) > maxCachedTags {
+		t.cache.Range(func(k, _ any) bool {
+			t.cache.Delete(k)
+			return true
+		})
+		t.cacheSize.Store(1)
+	}
+	t.cache.Store(tag, tagLookup{lvl: lvl, ok: ok})
+	return lvl, ok
+}
+
+// maxLevel returns the least severe level of
+// all patterns and def.
+func (t *tagLevels) maxLevel(def level.Level) level.Level {
+	for _, l := range t.levelMap() {
+		if l > def {
+			def = l
+		}
+	}
+	return def
+}
+
+type tagLookup struct {
+	lvl level.Level
+	ok  bool
+}
+
+// matchTagPattern returns true if the pattern
+// matches the tag or one of its parents. Each
+// segment of the pattern may contain wildcards
+// as supported by path.Match.
+func matchTagPattern(pattern, tag []string) bool {
+	if len(pattern) > len(tag) {
+		return false
+	}
+	for i, p := range pattern {
+		if p == tag[i] {
+			continue
+		}
+		if ok, _ := path.Match(p, tag[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func wildcardSegments(segments []string) (n int) {
+	for _, s := range segments {
+		if strings.ContainsAny(s, "*?[") {
+			n++
+		}
+	}
+	return n
+}
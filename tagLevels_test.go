@@ -0,0 +1,80 @@
+package rogu
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+func TestTagLevels(t *testing.T) {
+	var w recordWriter
+	l := NewLogger(&w).
+		SetLevel(level.Info).
+		SetTagLevel("Database", level.Trace).
+		SetTagLevel("http", level.Warn).
+		SetTagLevel("http.router", level.Debug).
+		SetTagLevel("*.cache", level.Error)
+
+	db := l.Tagged("Database")
+	router := l.Tagged("http.router.v2")
+
+	db.Trace().Msg("db trace")
+	l.Debug().Msg("untagged debug")
+	l.Info().Msg("untagged info")
+	l.Info().Tag("http.server").Msg("http info")
+	l.Warn().Tag("http").Msg("http warn")
+	router.Debug().Msg("router debug")
+	l.Warn().Tag("redis.cache").Msg("cache warn")
+	l.Info().Tag("other").Msg("other info")
+
+	// Changes are reflected in existing tagged loggers.
+	l.UnsetTagLevel("Database")
+	db.Trace().Msg("db trace after unset")
+	db.Info().Msg("db info after unset")
+
+	// The final tag of the event is checked.
+	db.Trace().Tag("http.router").Msg("retagged")
+
+	expected := []string{
+		"db trace",
+		"untagged info",
+		"http warn",
+		"router debug",
+		"other info",
+		"db info after unset",
+	}
+	if len(w.records) != len(expected) {
+		t.Fatalf("unexpected records: %v", w.records)
+	}
+	for i, msg := range expected {
+		if w.records[i]["msg"] != msg {
+			t.Errorf("record %d: expected %q, got %q", i, msg, w.records[i]["msg"])
+		}
+	}
+
+	if levels := l.TagLevels(); len(levels) != 3 || levels["http"] != level.Warn {
+		t.Errorf("unexpected tag levels: %v", levels)
+	}
+}
+
+func TestTagLevelsCacheLimit(t *testing.T) {
+	tl := newTagLevels(map[string]level.Level{"http.*": level.Debug})
+
+	for i := 0; i < 3*maxCachedTags; i++ {
+		tag := "http.req" + strconv.Itoa(i)
+		if lvl, ok := tl.lookup(tag); !ok || lvl != level.Debug {
+			t.Fatalf("%s: unexpected level %s (%t)", tag, lvl, ok)
+		}
+		tl.lookup("other" + strconv.Itoa(i))
+	}
+
+	var n int
+	tl.cache.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	if n > maxCachedTags {
+		t.Errorf("cache holds %d tags; want at most %d", n, maxCachedTags)
+	}
+}
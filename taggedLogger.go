@@ -6,42 +6,69 @@ import (
 
 type taggedLogger struct {
 	*logger
-	tag string
+	tag    string
+	fields []any
 }
 
 var _ Logger = (*taggedLogger)(nil)
 
+// Tagged returns a new logger which references
+// the origin logger but attaches the given
+// tag to every created Entry. Bound fields
+// of the origin logger are kept.
+func (t *taggedLogger) Tagged(tag string) Logger {
+	return &taggedLogger{
+		logger: t.logger,
+		tag:    tag,
+		fields: t.fields,
+	}
+}
+
+// With returns a new logger which references
+// the origin logger but adds the given fields
+// after the already bound fields to every
+// created Entry. The tag of the origin logger
+// is kept.
+func (t *taggedLogger) With(kv ...any) Logger {
+	return &taggedLogger{
+		logger: t.logger,
+		tag:    t.tag,
+		fields: appendFields(t.fields, kv),
+	}
+}
+
 // Trace creates a new log Event with level trace.
 func (t *taggedLogger) Trace() *Event {
-	return t.newEvent(level.Trace).Tag(t.tag)
+	return t.newEvent(level.Trace)
 }
 
 // Trace creates a new log Event with level debug.
 func (t *taggedLogger) Debug() *Event {
-	return t.newEvent(level.Debug).Tag(t.tag)
+	return t.newEvent(level.Debug)
 }
 
 // Trace creates a new log Event with info.
 func (t *taggedLogger) Info() *Event {
-	return t.newEvent(level.Info).Tag(t.tag)
+	return t.newEvent(level.Info)
 }
 
 // Trace creates a new log Event with level warn.
 func (t *taggedLogger) Warn() *Event {
-	return t.newEvent(level.Warn).Tag(t.tag)
+	return t.newEvent(level.Warn)
 }
 
 // Trace creates a new log Event with level error.
 func (t *taggedLogger) Error() *Event {
-	return t.newEvent(level.Error).Tag(t.tag)
+	return t.newEvent(level.Error)
 }
 
 // Trace creates a new log Event with level fatal.
 //
-// When commited, the programm will exit with exit
-// code 1.
+// When commited, all writers are closed and the
+// programm will exit with exit code 1 or the code
+// set via `Event.ExitCode`.
 func (t *taggedLogger) Fatal() *Event {
-	return t.newEvent(level.Fatal).Tag(t.tag)
+	return t.newEvent(level.Fatal)
 }
 
 // Trace creates a new log Event with level panic.
@@ -49,10 +76,30 @@ func (t *taggedLogger) Fatal() *Event {
 // When commited, the program will panic at the
 // called point.
 func (t *taggedLogger) Panic() *Event {
-	return t.newEvent(level.Panic).Tag(t.tag)
+	return t.newEvent(level.Panic)
 }
 
 // WithLevel returns a new log Event with the given level.
 func (t *taggedLogger) WithLevel(lvl level.Level) *Event {
-	return t.newEvent(lvl).Tag(t.tag)
+	return t.newEvent(lvl)
+}
+
+func (t *taggedLogger) newEvent(lvl level.Level) *Event {
+	return t.logger.newEvent(lvl).
+		Tag(t.tag).
+		Fields(t.fields...)
+}
+
+// appendFields returns a new slice containing the
+// fields of bound followed by kv. When kv has an
+// odd length, it is padded with a nil value so that
+// fields appended later stay aligned.
+func appendFields(bound []any, kv []any) []any {
+	fields := make([]any, 0, len(bound)+len(kv)+1)
+	fields = append(fields, bound...)
+	fields = append(fields, kv...)
+	if len(kv)%2 == 1 {
+		fields = append(fields, nil)
+	}
+	return fields
 }
@@ -0,0 +1,51 @@
+package rogu
+
+import (
+	"fmt"
+	"testing"
+)
+
+func fieldsString(e Entry) string {
+	var s string
+	for _, f := range e.Fields {
+		s += fmt.Sprintf("%s=%v ", f.KeyString(), f.Value())
+	}
+	return s
+}
+
+func TestLoggerWith(t *testing.T) {
+	var w entryWriter
+	l := NewLogger(&w)
+
+	base := l.With("a", 1)
+	chained := base.With("b", 2).With("c", 3)
+	sibling := base.With("d", 4)
+	odd := l.With("odd").With("e", 5)
+	tagged := l.Tagged("tag").With("f", 6).Tagged("other")
+
+	tests := []struct {
+		name   string
+		l      Logger
+		kv     []any
+		tag    string
+		fields string
+	}{
+		{"base", base, nil, "", "a=1 "},
+		{"chained", chained, nil, "", "a=1 b=2 c=3 "},
+		{"sibling", sibling, nil, "", "a=1 d=4 "},
+		{"odd", odd, nil, "", "odd=<nil> e=5 "},
+		{"tagged", tagged, nil, "other", "f=6 "},
+		{"event fields", chained, []any{"g", 7}, "", "a=1 b=2 c=3 g=7 "},
+	}
+
+	for _, tt := range tests {
+		w.entries = nil
+
+		tt.l.Info().Fields(tt.kv...).Msg("hello")
+
+		if got := w.entries[0]; got.Tag != tt.tag || fieldsString(got) != tt.fields {
+			t.Errorf("%s: unexpected entry with tag %q and fields %q; want %q and %q",
+				tt.name, got.Tag, fieldsString(got), tt.tag, tt.fields)
+		}
+	}
+}
@@ -1,10 +1,109 @@
 package rogu
 
-import "github.com/zekrotja/rogu/level"
+import (
+	"fmt"
+	"time"
 
-// Writer takes log entry components and
-// writes them somewhere.
+	"github.com/zekrotja/rogu/level"
+)
+
+// Entry holds all components of a commited
+// log event which are passed to a Writer.
+//
+// An Entry must be treated as immutable by
+// writers. The Fields are given back to a pool
+// after the entry has been written, so use
+// Clone when the entry needs to be retained
+// after Write has returned.
+type Entry struct {
+	Time       time.Time
+	Level      level.Level
+	Tag        string
+	Message    string
+	Fields     []*Field
+	Err        error
+	ErrFormat  string
+	Errs       []NamedError
+	CallerFile string
+	CallerLine int
+	Stack      []Frame
+}
+
+// ErrString returns the formatted error of
+// the entry or an empty string if the entry
+// has no error.
+func (t Entry) ErrString() string {
+	if t.Err == nil {
+		return ""
+	}
+	if t.ErrFormat != "" {
+		return fmt.Sprintf(t.ErrFormat, t.Err)
+	}
+	return t.Err.Error()
+}
+
+// NamedError is an additional error of an entry
+// added via `Event.NamedErr` or `Event.Errs`.
+type NamedError struct {
+	// Name is the name of the error, which is
+	// unique within the entry. Errors added via
+	// `Event.Errs` are numbered like `1`.
+	Name   string
+	Err    error
+	Format string
+	// Stack is the stack trace carried by the
+	// error, if any. It is only recorded when the
+	// stack trace of the entry is recorded.
+	Stack []Frame
+}
+
+// String returns the formatted error.
+func (t NamedError) String() string {
+	if t.Format != "" {
+		return fmt.Sprintf(t.Format, t.Err)
+	}
+	return t.Err.Error()
+}
+
+// Clone returns a copy of the entry with
+// its own copy of the fields so that it
+// can be retained after Write has returned.
+// The stack is not pooled and therefore shared
+// with the original entry.
+func (t Entry) Clone() Entry {
+	if len(t.Errs) > 0 {
+		t.Errs = append([]NamedError(nil), t.Errs...)
+	}
+
+	if len(t.Fields) == 0 {
+		t.Fields = nil
+		return t
+	}
+
+	fields := make([]Field, len(t.Fields))
+	ptrs := make([]*Field, len(t.Fields))
+	for i, f := range t.Fields {
+		fields[i] = *f
+		ptrs[i] = &fields[i]
+	}
+	t.Fields = ptrs
+
+	return t
+}
+
+// Writer takes log entries and writes
+// them somewhere.
 type Writer interface {
+	Write(e Entry) error
+}
+
+// LegacyWriter is the previous form of the Writer
+// interface which takes the components of a log
+// entry as separate parameters.
+//
+// Use AdaptLegacyWriter to use a LegacyWriter
+// as Writer.
+type LegacyWriter interface {
 	Write(
 		lvl level.Level,
 		fields []*Field,
@@ -17,6 +116,65 @@ type Writer interface {
 	) error
 }
 
+type legacyWriter struct {
+	w LegacyWriter
+}
+
+var (
+	_ Writer = legacyWriter{}
+	_ Closer = legacyWriter{}
+)
+
+// AdaptLegacyWriter returns a Writer which passes
+// the components of written entries to the
+// given LegacyWriter.
+func AdaptLegacyWriter(w LegacyWriter) Writer {
+	return legacyWriter{w: w}
+}
+
+func (t legacyWriter) Write(e Entry) error {
+	return t.w.Write(
+		e.Level,
+		legacyFields(e.Fields),
+		e.Tag,
+		e.Err,
+		e.ErrFormat,
+		e.CallerFile,
+		e.CallerLine,
+		e.Message,
+	)
+}
+
+// legacyFields returns the given fields with the
+// values of typed fields stored in Val, because
+// legacy writers only access the Val of fields.
+func legacyFields(fields []*Field) []*Field {
+	var res []*Field
+	for i, f := range fields {
+		if f.kind == KindAny {
+			continue
+		}
+		if res == nil {
+			res = make([]*Field, len(fields))
+			copy(res, fields)
+		}
+		nf := *f
+		nf.Val = f.Value()
+		res[i] = &nf
+	}
+	if res == nil {
+		return fields
+	}
+	return res
+}
+
+func (t legacyWriter) Close() error {
+	if c, ok := t.w.(Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // Closer is used to close stuff. 🤯
 type Closer interface {
 	Close() error
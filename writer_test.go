@@ -0,0 +1,54 @@
+package rogu
+
+import (
+	"testing"
+
+	"github.com/zekrotja/rogu/level"
+)
+
+type testLegacyWriter struct {
+	lvl    level.Level
+	tag    string
+	msg    string
+	fields int
+}
+
+func (t *testLegacyWriter) Write(
+	lvl level.Level,
+	fields []*Field,
+	tag string,
+	err error,
+	errFormat string,
+	callerFile string,
+	callerLine int,
+	msg string,
+) error {
+	t.lvl = lvl
+	t.tag = tag
+	t.msg = msg
+	t.fields = len(fields)
+	return nil
+}
+
+func TestAdaptLegacyWriter(t *testing.T) {
+	var w testLegacyWriter
+	l := NewLogger(AdaptLegacyWriter(&w))
+
+	l.Warn().Tag("tag").Field("a", 1).Msg("hello")
+
+	if w.lvl != level.Warn || w.tag != "tag" || w.msg != "hello" || w.fields != 1 {
+		t.Errorf("unexpected write: %+v", w)
+	}
+}
+
+func TestEntryClone(t *testing.T) {
+	f := &Field{Key: "a", Val: 1}
+	e := Entry{Fields: []*Field{f}}
+
+	c := e.Clone()
+	f.Reset()
+
+	if c.Fields[0].Key != "a" || c.Fields[0].Val != 1 {
+		t.Errorf("cloned field was modified: %+v", c.Fields[0])
+	}
+}